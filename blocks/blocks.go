@@ -0,0 +1,374 @@
+// Package blocks implements the Block Kit layout blocks and elements to compose rich messages and views.
+// see. https://api.slack.com/block-kit
+package blocks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+)
+
+// Slack's limits of the number of blocks.
+const (
+	// MaxMessageBlocks is the maximum number of blocks in a message.
+	MaxMessageBlocks = 50
+
+	// MaxViewBlocks is the maximum number of blocks in a modal or home tab view.
+	MaxViewBlocks = 100
+)
+
+// Block types.
+const (
+	SectionType = "section"
+	HeaderType  = "header"
+	DividerType = "divider"
+	ContextType = "context"
+	ActionsType = "actions"
+	ImageType   = "image"
+	InputType   = "input"
+)
+
+// Block represents a layout block.
+// see. https://api.slack.com/reference/block-kit/blocks
+type Block interface {
+	BlockType() string
+	Validate() error
+}
+
+// Element represents a block element or a composition object used as an element.
+// see. https://api.slack.com/reference/block-kit/block-elements
+type Element interface {
+	ElementType() string
+	Validate() error
+}
+
+// Blocks is the list of blocks of a message.
+type Blocks []Block
+
+// Validate validates the blocks as the blocks of a message.
+func (bs Blocks) Validate() error {
+	return validateBlocks(bs, MaxMessageBlocks)
+}
+
+func validateBlocks(bs []Block, limit int) error {
+	if len(bs) > limit {
+		return fmt.Errorf("too many blocks: %d > %d", len(bs), limit)
+	}
+	for i, b := range bs {
+		if b == nil {
+			return fmt.Errorf("blocks[%d]: nil block", i)
+		}
+		if err := b.Validate(); err != nil {
+			return fmt.Errorf("blocks[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Section is a block to display text, possibly alongside an accessory element.
+// see. https://api.slack.com/reference/block-kit/blocks#section
+type Section struct {
+	BlockID   string  `json:"block_id,omitempty"`
+	Text      *Text   `json:"text,omitempty"`
+	Fields    []*Text `json:"fields,omitempty"`
+	Accessory Element `json:"accessory,omitempty"`
+}
+
+// NewSection creates a section block with the text.
+func NewSection(text *Text, fields ...*Text) *Section {
+	return &Section{Text: text, Fields: fields}
+}
+
+// BlockType implements the Block interface.
+func (Section) BlockType() string { return SectionType }
+
+// Validate implements the Block interface.
+func (b Section) Validate() error {
+	if err := validateBlockID(SectionType, b.BlockID); err != nil {
+		return err
+	}
+	if b.Text == nil && len(b.Fields) == 0 {
+		return fmt.Errorf("section: text or fields required")
+	}
+	if err := b.Text.validate("section: text", 3000); err != nil {
+		return err
+	}
+	if len(b.Fields) > 10 {
+		return fmt.Errorf("section: too many fields: %d > 10", len(b.Fields))
+	}
+	for i, v := range b.Fields {
+		if v == nil {
+			return fmt.Errorf("section: fields[%d]: nil text", i)
+		}
+		if err := v.validate(fmt.Sprintf("section: fields[%d]", i), 2000); err != nil {
+			return err
+		}
+	}
+	if b.Accessory != nil {
+		if err := b.Accessory.Validate(); err != nil {
+			return fmt.Errorf("section: accessory: %w", err)
+		}
+	}
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (b Section) MarshalJSON() ([]byte, error) {
+	type alias Section
+	return marshalWithType(SectionType, alias(b))
+}
+
+// Header is a block to display a larger-sized plain text.
+// see. https://api.slack.com/reference/block-kit/blocks#header
+type Header struct {
+	BlockID string `json:"block_id,omitempty"`
+	Text    *Text  `json:"text"`
+}
+
+// NewHeader creates a header block with the plain text.
+func NewHeader(text string) *Header {
+	return &Header{Text: PlainText(text)}
+}
+
+// BlockType implements the Block interface.
+func (Header) BlockType() string { return HeaderType }
+
+// Validate implements the Block interface.
+func (b Header) Validate() error {
+	if err := validateBlockID(HeaderType, b.BlockID); err != nil {
+		return err
+	}
+	return b.Text.validatePlain("header: text", 150, true)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (b Header) MarshalJSON() ([]byte, error) {
+	type alias Header
+	return marshalWithType(HeaderType, alias(b))
+}
+
+// Divider is a block to separate other blocks.
+// see. https://api.slack.com/reference/block-kit/blocks#divider
+type Divider struct {
+	BlockID string `json:"block_id,omitempty"`
+}
+
+// NewDivider creates a divider block.
+func NewDivider() *Divider {
+	return &Divider{}
+}
+
+// BlockType implements the Block interface.
+func (Divider) BlockType() string { return DividerType }
+
+// Validate implements the Block interface.
+func (b Divider) Validate() error {
+	return validateBlockID(DividerType, b.BlockID)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (b Divider) MarshalJSON() ([]byte, error) {
+	type alias Divider
+	return marshalWithType(DividerType, alias(b))
+}
+
+// Context is a block to display texts and images as contextual info.
+// see. https://api.slack.com/reference/block-kit/blocks#context
+type Context struct {
+	BlockID  string    `json:"block_id,omitempty"`
+	Elements []Element `json:"elements"`
+}
+
+// NewContext creates a context block with the texts and images.
+func NewContext(elements ...Element) *Context {
+	return &Context{Elements: elements}
+}
+
+// BlockType implements the Block interface.
+func (Context) BlockType() string { return ContextType }
+
+// Validate implements the Block interface.
+func (b Context) Validate() error {
+	if err := validateBlockID(ContextType, b.BlockID); err != nil {
+		return err
+	}
+	if len(b.Elements) == 0 || len(b.Elements) > 10 {
+		return fmt.Errorf("context: number of elements out of range [1, 10]: %d", len(b.Elements))
+	}
+	for i, v := range b.Elements {
+		switch v.(type) {
+		case *Text, Text, *ImageElement, ImageElement:
+		default:
+			return fmt.Errorf("context: elements[%d]: unsupported element: %T", i, v)
+		}
+		if err := v.Validate(); err != nil {
+			return fmt.Errorf("context: elements[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (b Context) MarshalJSON() ([]byte, error) {
+	type alias Context
+	return marshalWithType(ContextType, alias(b))
+}
+
+// Actions is a block to hold interactive elements.
+// see. https://api.slack.com/reference/block-kit/blocks#actions
+type Actions struct {
+	BlockID  string    `json:"block_id,omitempty"`
+	Elements []Element `json:"elements"`
+}
+
+// NewActions creates an actions block with the interactive elements.
+func NewActions(elements ...Element) *Actions {
+	return &Actions{Elements: elements}
+}
+
+// BlockType implements the Block interface.
+func (Actions) BlockType() string { return ActionsType }
+
+// Validate implements the Block interface.
+func (b Actions) Validate() error {
+	if err := validateBlockID(ActionsType, b.BlockID); err != nil {
+		return err
+	}
+	if len(b.Elements) == 0 || len(b.Elements) > 25 {
+		return fmt.Errorf("actions: number of elements out of range [1, 25]: %d", len(b.Elements))
+	}
+	for i, v := range b.Elements {
+		if v == nil {
+			return fmt.Errorf("actions: elements[%d]: nil element", i)
+		}
+		if err := v.Validate(); err != nil {
+			return fmt.Errorf("actions: elements[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (b Actions) MarshalJSON() ([]byte, error) {
+	type alias Actions
+	return marshalWithType(ActionsType, alias(b))
+}
+
+// Image is a block to display an image.
+// see. https://api.slack.com/reference/block-kit/blocks#image
+type Image struct {
+	BlockID  string `json:"block_id,omitempty"`
+	ImageURL string `json:"image_url"`
+	AltText  string `json:"alt_text"`
+	Title    *Text  `json:"title,omitempty"`
+}
+
+// NewImage creates an image block.
+func NewImage(imageURL, altText string) *Image {
+	return &Image{ImageURL: imageURL, AltText: altText}
+}
+
+// BlockType implements the Block interface.
+func (Image) BlockType() string { return ImageType }
+
+// Validate implements the Block interface.
+func (b Image) Validate() error {
+	if err := validateBlockID(ImageType, b.BlockID); err != nil {
+		return err
+	}
+	if err := validateLength("image: image_url", b.ImageURL, 3000, true); err != nil {
+		return err
+	}
+	if err := validateLength("image: alt_text", b.AltText, 2000, true); err != nil {
+		return err
+	}
+	return b.Title.validatePlain("image: title", 2000, false)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (b Image) MarshalJSON() ([]byte, error) {
+	type alias Image
+	return marshalWithType(ImageType, alias(b))
+}
+
+// Input is a block to collect information from users.
+// see. https://api.slack.com/reference/block-kit/blocks#input
+type Input struct {
+	BlockID        string  `json:"block_id,omitempty"`
+	Label          *Text   `json:"label"`
+	Element        Element `json:"element"`
+	DispatchAction bool    `json:"dispatch_action,omitempty"`
+	Hint           *Text   `json:"hint,omitempty"`
+	Optional       bool    `json:"optional,omitempty"`
+}
+
+// NewInput creates an input block with the label and the element.
+func NewInput(label string, element Element) *Input {
+	return &Input{Label: PlainText(label), Element: element}
+}
+
+// BlockType implements the Block interface.
+func (Input) BlockType() string { return InputType }
+
+// Validate implements the Block interface.
+func (b Input) Validate() error {
+	if err := validateBlockID(InputType, b.BlockID); err != nil {
+		return err
+	}
+	if err := b.Label.validatePlain("input: label", 2000, true); err != nil {
+		return err
+	}
+	if err := b.Hint.validatePlain("input: hint", 2000, false); err != nil {
+		return err
+	}
+	if b.Element == nil {
+		return fmt.Errorf("input: element required")
+	}
+	if err := b.Element.Validate(); err != nil {
+		return fmt.Errorf("input: element: %w", err)
+	}
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (b Input) MarshalJSON() ([]byte, error) {
+	type alias Input
+	return marshalWithType(InputType, alias(b))
+}
+
+// marshalWithType marshals the value as a JSON object with the type field.
+func marshalWithType(typ string, v interface{}) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	t, err := json.Marshal(typ)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	buf.WriteString(`{"type":`)
+	buf.Write(t)
+	if len(b) > 2 {
+		buf.WriteByte(',')
+		buf.Write(b[1:])
+	} else {
+		buf.WriteByte('}')
+	}
+	return buf.Bytes(), nil
+}
+
+func validateBlockID(typ, id string) error {
+	return validateLength(typ+": block_id", id, 255, false)
+}
+
+func validateLength(field, s string, limit int, required bool) error {
+	if required && s == "" {
+		return fmt.Errorf("%s required", field)
+	}
+	if n := utf8.RuneCountInString(s); n > limit {
+		return fmt.Errorf("%s exceeds %d characters: %d", field, limit, n)
+	}
+	return nil
+}
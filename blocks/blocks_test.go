@@ -0,0 +1,289 @@
+package blocks_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ikawaha/slackbot/blocks"
+)
+
+type validator interface {
+	Validate() error
+}
+
+func options(n int) []*blocks.Option {
+	ret := make([]*blocks.Option, 0, n)
+	for i := 0; i < n; i++ {
+		ret = append(ret, blocks.NewOption("option", "value"))
+	}
+	return ret
+}
+
+func texts(n int) []*blocks.Text {
+	ret := make([]*blocks.Text, 0, n)
+	for i := 0; i < n; i++ {
+		ret = append(ret, blocks.Markdown("field"))
+	}
+	return ret
+}
+
+func elements(n int) []blocks.Element {
+	ret := make([]blocks.Element, 0, n)
+	for i := 0; i < n; i++ {
+		ret = append(ret, blocks.NewButton("a", "button", "v"))
+	}
+	return ret
+}
+
+func TestValidate(t *testing.T) {
+	long := func(n int) string { return strings.Repeat("あ", n) }
+	tests := []struct {
+		name    string
+		v       validator
+		wantErr string
+	}{
+		// texts
+		{name: "plain text", v: blocks.PlainText("hello")},
+		{name: "markdown", v: blocks.Markdown("*hello*")},
+		{name: "text of unknown type", v: blocks.Text{Type: "html", Text: "x"}, wantErr: "unknown type"},
+		{name: "empty text", v: blocks.Markdown(""), wantErr: "text required"},
+		{name: "text at the limit", v: blocks.Markdown(long(3000))},
+		{name: "text over the limit", v: blocks.Markdown(long(3001)), wantErr: "exceeds 3000 characters"},
+
+		// section
+		{name: "section", v: blocks.NewSection(blocks.Markdown("x"), texts(10)...)},
+		{name: "section without text and fields", v: &blocks.Section{}, wantErr: "text or fields required"},
+		{name: "section with too many fields", v: blocks.NewSection(nil, texts(11)...), wantErr: "too many fields"},
+		{name: "section field over the limit", v: blocks.NewSection(nil, blocks.Markdown(long(2001))), wantErr: "fields[0] exceeds 2000"},
+		{name: "section with nil field", v: blocks.NewSection(nil, nil), wantErr: "fields[0]: nil text"},
+		{name: "section with invalid accessory", v: &blocks.Section{Text: blocks.Markdown("x"), Accessory: blocks.NewOverflow("a")}, wantErr: "section: accessory"},
+		{name: "section with long block_id", v: &blocks.Section{BlockID: long(256), Text: blocks.Markdown("x")}, wantErr: "block_id exceeds 255"},
+
+		// header
+		{name: "header", v: blocks.NewHeader(long(150))},
+		{name: "header over the limit", v: blocks.NewHeader(long(151)), wantErr: "exceeds 150"},
+		{name: "header with markdown", v: &blocks.Header{Text: blocks.Markdown("x")}, wantErr: "must be plain_text"},
+		{name: "header without text", v: &blocks.Header{}, wantErr: "header: text required"},
+
+		// divider
+		{name: "divider", v: blocks.NewDivider()},
+
+		// context
+		{name: "context", v: blocks.NewContext(blocks.Markdown("x"), blocks.NewImageElement("https://example.com/a.png", "a"))},
+		{name: "empty context", v: blocks.NewContext(), wantErr: "out of range [1, 10]: 0"},
+		{name: "context with too many elements", v: blocks.NewContext(textElements(11)...), wantErr: "out of range [1, 10]: 11"},
+		{name: "context with a button", v: blocks.NewContext(blocks.NewButton("a", "b", "v")), wantErr: "unsupported element"},
+
+		// actions
+		{name: "actions", v: blocks.NewActions(elements(25)...)},
+		{name: "empty actions", v: blocks.NewActions(), wantErr: "out of range [1, 25]: 0"},
+		{name: "actions with too many elements", v: blocks.NewActions(elements(26)...), wantErr: "out of range [1, 25]: 26"},
+		{name: "actions with nil element", v: blocks.NewActions(nil), wantErr: "nil element"},
+
+		// image
+		{name: "image", v: blocks.NewImage("https://example.com/a.png", "a")},
+		{name: "image without url", v: blocks.NewImage("", "a"), wantErr: "image_url required"},
+		{name: "image without alt text", v: blocks.NewImage("https://example.com/a.png", ""), wantErr: "alt_text required"},
+
+		// input
+		{name: "input", v: blocks.NewInput("title", blocks.NewPlainTextInput("a", "placeholder"))},
+		{name: "input without element", v: blocks.NewInput("title", nil), wantErr: "element required"},
+		{name: "input without label", v: &blocks.Input{Element: blocks.NewPlainTextInput("a", "")}, wantErr: "label required"},
+		{name: "input with long label", v: blocks.NewInput(long(2001), blocks.NewPlainTextInput("a", "")), wantErr: "label exceeds 2000"},
+
+		// button
+		{name: "button", v: blocks.NewButton("a", long(75), "v")},
+		{name: "button with long text", v: blocks.NewButton("a", long(76), "v"), wantErr: "button: text exceeds 75"},
+		{name: "button with long action_id", v: blocks.NewButton(long(256), "b", "v"), wantErr: "action_id exceeds 255"},
+		{name: "button with unknown style", v: &blocks.Button{Text: blocks.PlainText("b"), Style: "secondary"}, wantErr: "unknown style"},
+		{name: "button with confirm", v: &blocks.Button{Text: blocks.PlainText("b"), Style: "danger", Confirm: blocks.NewConfirm("sure?", "really?", "yes", "no")}},
+		{name: "button with invalid confirm", v: &blocks.Button{Text: blocks.PlainText("b"), Confirm: blocks.NewConfirm("sure?", "really?", long(31), "no")}, wantErr: "confirm: confirm exceeds 30"},
+
+		// static select
+		{name: "static select", v: blocks.NewStaticSelect("a", "choose", options(100)...)},
+		{name: "static select without options", v: blocks.NewStaticSelect("a", "choose"), wantErr: "out of range [1, 100]: 0"},
+		{name: "static select with too many options", v: blocks.NewStaticSelect("a", "choose", options(101)...), wantErr: "out of range [1, 100]: 101"},
+		{name: "static select with option groups", v: &blocks.StaticSelect{OptionGroups: []*blocks.OptionGroup{blocks.NewOptionGroup("group", options(2)...)}}},
+		{
+			name:    "static select with options and option groups",
+			v:       &blocks.StaticSelect{Options: options(1), OptionGroups: []*blocks.OptionGroup{blocks.NewOptionGroup("group", options(1)...)}},
+			wantErr: "options and option_groups are exclusive",
+		},
+		{name: "static select with empty option group", v: &blocks.StaticSelect{OptionGroups: []*blocks.OptionGroup{blocks.NewOptionGroup("group")}}, wantErr: "option_groups[0]"},
+		{name: "static select with long placeholder", v: blocks.NewStaticSelect("a", long(151), options(1)...), wantErr: "placeholder exceeds 150"},
+
+		// options
+		{name: "option with long text", v: blocks.NewOption(long(76), "v"), wantErr: "option: text exceeds 75"},
+		{name: "option without value", v: blocks.NewOption("x", ""), wantErr: "option: value required"},
+		{name: "option with long value", v: blocks.NewOption("x", long(151)), wantErr: "option: value exceeds 150"},
+
+		// other elements
+		{name: "users select", v: blocks.NewUsersSelect("a", "user")},
+		{name: "conversations select", v: blocks.NewConversationsSelect("a", "conversation")},
+		{name: "channels select", v: blocks.NewChannelsSelect("a", "channel")},
+		{name: "date picker", v: &blocks.DatePicker{InitialDate: "2026-10-17"}},
+		{name: "date picker with invalid date", v: &blocks.DatePicker{InitialDate: "10/17/2026"}, wantErr: "must be YYYY-MM-DD"},
+		{name: "overflow", v: blocks.NewOverflow("a", options(5)...)},
+		{name: "overflow with too many options", v: blocks.NewOverflow("a", options(6)...), wantErr: "out of range [1, 5]: 6"},
+		{name: "plain text input", v: &blocks.PlainTextInput{MinLength: 1, MaxLength: 3000}},
+		{name: "plain text input with max less than min", v: &blocks.PlainTextInput{MinLength: 10, MaxLength: 5}, wantErr: "invalid max_length"},
+		{name: "plain text input with long min", v: &blocks.PlainTextInput{MinLength: 3001}, wantErr: "min_length out of range"},
+		{name: "checkboxes", v: blocks.NewCheckboxes("a", options(10)...)},
+		{name: "checkboxes with too many options", v: blocks.NewCheckboxes("a", options(11)...), wantErr: "out of range [1, 10]: 11"},
+		{name: "radio buttons", v: blocks.NewRadioButtons("a", options(10)...)},
+		{name: "radio buttons without options", v: blocks.NewRadioButtons("a"), wantErr: "out of range [1, 10]: 0"},
+
+		// blocks of a message
+		{name: "message blocks", v: blocks.Blocks{blocks.NewDivider(), blocks.NewHeader("x")}},
+		{name: "too many message blocks", v: dividers(blocks.MaxMessageBlocks + 1), wantErr: "too many blocks: 51 > 50"},
+		{name: "nil message block", v: blocks.Blocks{nil}, wantErr: "blocks[0]: nil block"},
+		{name: "invalid message block", v: blocks.Blocks{blocks.NewDivider(), blocks.NewHeader("")}, wantErr: "blocks[1]: header: text"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.v.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("want error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func textElements(n int) []blocks.Element {
+	ret := make([]blocks.Element, 0, n)
+	for i := 0; i < n; i++ {
+		ret = append(ret, blocks.Markdown("x"))
+	}
+	return ret
+}
+
+func dividers(n int) blocks.Blocks {
+	ret := make(blocks.Blocks, 0, n)
+	for i := 0; i < n; i++ {
+		ret = append(ret, blocks.NewDivider())
+	}
+	return ret
+}
+
+func TestView_Validate(t *testing.T) {
+	input := blocks.NewInput("title", blocks.NewPlainTextInput("a", ""))
+	viewBlocks := make([]blocks.Block, blocks.MaxViewBlocks+1)
+	for i := range viewBlocks {
+		viewBlocks[i] = blocks.NewDivider()
+	}
+	withSubmit := func(v *blocks.View) *blocks.View {
+		v.Submit = blocks.PlainText("Submit")
+		return v
+	}
+	tests := []struct {
+		name    string
+		v       *blocks.View
+		wantErr string
+	}{
+		{name: "modal", v: blocks.NewModal("title", blocks.NewSection(blocks.Markdown("x")))},
+		{name: "modal with input and submit", v: withSubmit(blocks.NewModal("title", input))},
+		{name: "modal with input without submit", v: blocks.NewModal("title", input), wantErr: "submit required for a modal with input blocks"},
+		{name: "modal without title", v: &blocks.View{Type: blocks.ModalType}, wantErr: "view: title required"},
+		{name: "modal with long title", v: blocks.NewModal(strings.Repeat("x", 25)), wantErr: "view: title exceeds 24"},
+		{name: "modal with long submit", v: &blocks.View{Type: blocks.ModalType, Title: blocks.PlainText("t"), Submit: blocks.PlainText(strings.Repeat("x", 25))}, wantErr: "view: submit exceeds 24"},
+		{name: "home", v: blocks.NewHome(viewBlocks[:blocks.MaxViewBlocks]...)},
+		{name: "home with too many blocks", v: blocks.NewHome(viewBlocks...), wantErr: "too many blocks: 101 > 100"},
+		{name: "unknown type", v: &blocks.View{Type: "workflow_step"}, wantErr: "unknown type"},
+		{name: "long private metadata", v: &blocks.View{Type: blocks.HomeType, PrivateMetadata: strings.Repeat("x", 3001)}, wantErr: "private_metadata exceeds 3000"},
+		{name: "long callback id", v: &blocks.View{Type: blocks.HomeType, CallbackID: strings.Repeat("x", 256)}, wantErr: "callback_id exceeds 255"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.v.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("want error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestMarshalJSON_Type(t *testing.T) {
+	tests := []struct {
+		v    interface{}
+		want string
+	}{
+		{v: blocks.NewSection(blocks.Markdown("x")), want: blocks.SectionType},
+		{v: blocks.NewHeader("x"), want: blocks.HeaderType},
+		{v: blocks.NewDivider(), want: blocks.DividerType},
+		{v: blocks.NewContext(blocks.Markdown("x")), want: blocks.ContextType},
+		{v: blocks.NewActions(blocks.NewButton("a", "b", "v")), want: blocks.ActionsType},
+		{v: blocks.NewImage("https://example.com/a.png", "a"), want: blocks.ImageType},
+		{v: blocks.NewInput("l", blocks.NewPlainTextInput("a", "")), want: blocks.InputType},
+		{v: blocks.NewButton("a", "b", "v"), want: blocks.ButtonType},
+		{v: blocks.NewStaticSelect("a", "p", options(1)...), want: blocks.StaticSelectType},
+		{v: blocks.NewUsersSelect("a", "p"), want: blocks.UsersSelectType},
+		{v: blocks.NewConversationsSelect("a", "p"), want: blocks.ConversationsSelectType},
+		{v: blocks.NewChannelsSelect("a", "p"), want: blocks.ChannelsSelectType},
+		{v: blocks.NewDatePicker("a", "p"), want: blocks.DatePickerType},
+		{v: blocks.NewOverflow("a", options(1)...), want: blocks.OverflowType},
+		{v: blocks.NewPlainTextInput("a", "p"), want: blocks.PlainTextInputType},
+		{v: blocks.NewCheckboxes("a", options(1)...), want: blocks.CheckboxesType},
+		{v: blocks.NewRadioButtons("a", options(1)...), want: blocks.RadioButtonsType},
+		{v: blocks.NewImageElement("https://example.com/a.png", "a"), want: blocks.ImageElementType},
+		{v: blocks.PlainText("x"), want: blocks.PlainTextType},
+		{v: blocks.Markdown("x"), want: blocks.MarkdownType},
+		{v: blocks.NewModal("t"), want: blocks.ModalType},
+		{v: blocks.NewHome(), want: blocks.HomeType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			b, err := json.Marshal(tt.v)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var got map[string]interface{}
+			if err := json.Unmarshal(b, &got); err != nil {
+				t.Fatalf("unexpected error: %v, %s", err, b)
+			}
+			if got["type"] != tt.want {
+				t.Errorf("want type %q, got %s", tt.want, b)
+			}
+		})
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		v    interface{}
+		want string
+	}{
+		{name: "divider", v: blocks.NewDivider(), want: `{"type":"divider"}`},
+		{name: "divider with block id", v: &blocks.Divider{BlockID: "d1"}, want: `{"type":"divider","block_id":"d1"}`},
+		{
+			name: "section with accessory",
+			v:    &blocks.Section{Text: blocks.Markdown("*x*"), Accessory: blocks.NewButton("a", "b", "v")},
+			want: `{"type":"section","text":{"type":"mrkdwn","text":"*x*"},"accessory":{"type":"button","action_id":"a","text":{"type":"plain_text","text":"b","emoji":true},"value":"v"}}`,
+		},
+		{name: "home without blocks", v: blocks.NewHome(), want: `{"type":"home","blocks":[]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(tt.v)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(b) != tt.want {
+				t.Errorf("want %s, got %s", tt.want, b)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package blocks
+
+import (
+	"fmt"
+)
+
+// Text object types.
+const (
+	PlainTextType = "plain_text"
+	MarkdownType  = "mrkdwn"
+)
+
+// Text is the text object.
+// see. https://api.slack.com/reference/block-kit/composition-objects#text
+type Text struct {
+	Type     string `json:"type"`
+	Text     string `json:"text"`
+	Emoji    bool   `json:"emoji,omitempty"`
+	Verbatim bool   `json:"verbatim,omitempty"`
+}
+
+// PlainText creates a plain_text text object.
+func PlainText(s string) *Text {
+	return &Text{Type: PlainTextType, Text: s, Emoji: true}
+}
+
+// Markdown creates a mrkdwn text object.
+func Markdown(s string) *Text {
+	return &Text{Type: MarkdownType, Text: s}
+}
+
+// ElementType implements the Element interface, so that texts can be used in the context block.
+func (t Text) ElementType() string { return t.Type }
+
+// Validate implements the Element interface.
+func (t Text) Validate() error {
+	switch t.Type {
+	case PlainTextType, MarkdownType:
+	default:
+		return fmt.Errorf("text: unknown type: %q", t.Type)
+	}
+	return validateLength("text", t.Text, 3000, true)
+}
+
+// validate validates the text if the text is not nil.
+func (t *Text) validate(field string, limit int) error {
+	if t == nil {
+		return nil
+	}
+	if err := t.Validate(); err != nil {
+		return fmt.Errorf("%s: %w", field, err)
+	}
+	return validateLength(field, t.Text, limit, true)
+}
+
+// validatePlain validates the text is a plain_text within the limit.
+func (t *Text) validatePlain(field string, limit int, required bool) error {
+	if t == nil {
+		if required {
+			return fmt.Errorf("%s required", field)
+		}
+		return nil
+	}
+	if t.Type != PlainTextType {
+		return fmt.Errorf("%s: must be %s: %q", field, PlainTextType, t.Type)
+	}
+	return t.validate(field, limit)
+}
+
+// Option is the option object used in selects, overflow menus, checkboxes and radio buttons.
+// see. https://api.slack.com/reference/block-kit/composition-objects#option
+type Option struct {
+	Text        *Text  `json:"text"`
+	Value       string `json:"value"`
+	Description *Text  `json:"description,omitempty"`
+	URL         string `json:"url,omitempty"`
+}
+
+// NewOption creates an option object with the plain text.
+func NewOption(text, value string) *Option {
+	return &Option{Text: PlainText(text), Value: value}
+}
+
+// Validate validates the option.
+func (o Option) Validate() error {
+	if o.Text == nil {
+		return fmt.Errorf("option: text required")
+	}
+	if err := o.Text.validate("option: text", 75); err != nil {
+		return err
+	}
+	if err := validateLength("option: value", o.Value, 150, true); err != nil {
+		return err
+	}
+	if err := o.Description.validate("option: description", 75); err != nil {
+		return err
+	}
+	return validateLength("option: url", o.URL, 3000, false)
+}
+
+// OptionGroup is the group of options in the select menu.
+// see. https://api.slack.com/reference/block-kit/composition-objects#option_group
+type OptionGroup struct {
+	Label   *Text     `json:"label"`
+	Options []*Option `json:"options"`
+}
+
+// NewOptionGroup creates an option group with the label.
+func NewOptionGroup(label string, options ...*Option) *OptionGroup {
+	return &OptionGroup{Label: PlainText(label), Options: options}
+}
+
+// Validate validates the option group.
+func (g OptionGroup) Validate() error {
+	if err := g.Label.validatePlain("option_group: label", 75, true); err != nil {
+		return err
+	}
+	return validateOptions("option_group: options", g.Options, 1, 100)
+}
+
+// Confirm is the confirmation dialog object.
+// see. https://api.slack.com/reference/block-kit/composition-objects#confirm
+type Confirm struct {
+	Title   *Text  `json:"title"`
+	Text    *Text  `json:"text"`
+	Confirm *Text  `json:"confirm"`
+	Deny    *Text  `json:"deny"`
+	Style   string `json:"style,omitempty"`
+}
+
+// NewConfirm creates a confirmation dialog object.
+func NewConfirm(title, text, confirm, deny string) *Confirm {
+	return &Confirm{
+		Title:   PlainText(title),
+		Text:    PlainText(text),
+		Confirm: PlainText(confirm),
+		Deny:    PlainText(deny),
+	}
+}
+
+// Validate validates the confirmation dialog.
+func (c *Confirm) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if err := c.Title.validatePlain("confirm: title", 100, true); err != nil {
+		return err
+	}
+	if c.Text == nil {
+		return fmt.Errorf("confirm: text required")
+	}
+	if err := c.Text.validate("confirm: text", 300); err != nil {
+		return err
+	}
+	if err := c.Confirm.validatePlain("confirm: confirm", 30, true); err != nil {
+		return err
+	}
+	if err := c.Deny.validatePlain("confirm: deny", 30, true); err != nil {
+		return err
+	}
+	return validateStyle("confirm", c.Style)
+}
+
+func validateOptions(field string, options []*Option, min, max int) error {
+	if len(options) < min || len(options) > max {
+		return fmt.Errorf("%s: number of options out of range [%d, %d]: %d", field, min, max, len(options))
+	}
+	for i, v := range options {
+		if v == nil {
+			return fmt.Errorf("%s[%d]: nil option", field, i)
+		}
+		if err := v.Validate(); err != nil {
+			return fmt.Errorf("%s[%d]: %w", field, i, err)
+		}
+	}
+	return nil
+}
+
+func validateStyle(field, style string) error {
+	switch style {
+	case "", "primary", "danger":
+		return nil
+	}
+	return fmt.Errorf("%s: unknown style: %q", field, style)
+}
@@ -0,0 +1,459 @@
+package blocks
+
+import (
+	"fmt"
+	"time"
+)
+
+// Element types.
+const (
+	ButtonType              = "button"
+	StaticSelectType        = "static_select"
+	UsersSelectType         = "users_select"
+	ConversationsSelectType = "conversations_select"
+	ChannelsSelectType      = "channels_select"
+	DatePickerType          = "datepicker"
+	OverflowType            = "overflow"
+	PlainTextInputType      = "plain_text_input"
+	CheckboxesType          = "checkboxes"
+	RadioButtonsType        = "radio_buttons"
+	ImageElementType        = "image"
+)
+
+// Button is an interactive element to click.
+// see. https://api.slack.com/reference/block-kit/block-elements#button
+type Button struct {
+	ActionID           string   `json:"action_id,omitempty"`
+	Text               *Text    `json:"text"`
+	URL                string   `json:"url,omitempty"`
+	Value              string   `json:"value,omitempty"`
+	Style              string   `json:"style,omitempty"`
+	Confirm            *Confirm `json:"confirm,omitempty"`
+	AccessibilityLabel string   `json:"accessibility_label,omitempty"`
+}
+
+// NewButton creates a button element.
+func NewButton(actionID, text, value string) *Button {
+	return &Button{ActionID: actionID, Text: PlainText(text), Value: value}
+}
+
+// ElementType implements the Element interface.
+func (Button) ElementType() string { return ButtonType }
+
+// Validate implements the Element interface.
+func (e Button) Validate() error {
+	if err := validateActionID(ButtonType, e.ActionID); err != nil {
+		return err
+	}
+	if err := e.Text.validatePlain("button: text", 75, true); err != nil {
+		return err
+	}
+	if err := validateLength("button: url", e.URL, 3000, false); err != nil {
+		return err
+	}
+	if err := validateLength("button: value", e.Value, 2000, false); err != nil {
+		return err
+	}
+	if err := validateStyle("button", e.Style); err != nil {
+		return err
+	}
+	if err := validateLength("button: accessibility_label", e.AccessibilityLabel, 75, false); err != nil {
+		return err
+	}
+	return e.Confirm.Validate()
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (e Button) MarshalJSON() ([]byte, error) {
+	type alias Button
+	return marshalWithType(ButtonType, alias(e))
+}
+
+// StaticSelect is a select menu with the static list of options.
+// see. https://api.slack.com/reference/block-kit/block-elements#static_select
+type StaticSelect struct {
+	ActionID      string         `json:"action_id,omitempty"`
+	Placeholder   *Text          `json:"placeholder,omitempty"`
+	Options       []*Option      `json:"options,omitempty"`
+	OptionGroups  []*OptionGroup `json:"option_groups,omitempty"`
+	InitialOption *Option        `json:"initial_option,omitempty"`
+	Confirm       *Confirm       `json:"confirm,omitempty"`
+}
+
+// NewStaticSelect creates a static select menu.
+func NewStaticSelect(actionID, placeholder string, options ...*Option) *StaticSelect {
+	return &StaticSelect{ActionID: actionID, Placeholder: placeholderText(placeholder), Options: options}
+}
+
+// ElementType implements the Element interface.
+func (StaticSelect) ElementType() string { return StaticSelectType }
+
+// Validate implements the Element interface.
+func (e StaticSelect) Validate() error {
+	if err := validateActionID(StaticSelectType, e.ActionID); err != nil {
+		return err
+	}
+	if err := e.Placeholder.validatePlain("static_select: placeholder", 150, false); err != nil {
+		return err
+	}
+	switch {
+	case len(e.Options) > 0 && len(e.OptionGroups) > 0:
+		return fmt.Errorf("static_select: options and option_groups are exclusive")
+	case len(e.OptionGroups) > 0:
+		if len(e.OptionGroups) > 100 {
+			return fmt.Errorf("static_select: too many option_groups: %d > 100", len(e.OptionGroups))
+		}
+		for i, v := range e.OptionGroups {
+			if v == nil {
+				return fmt.Errorf("static_select: option_groups[%d]: nil option group", i)
+			}
+			if err := v.Validate(); err != nil {
+				return fmt.Errorf("static_select: option_groups[%d]: %w", i, err)
+			}
+		}
+	default:
+		if err := validateOptions("static_select: options", e.Options, 1, 100); err != nil {
+			return err
+		}
+	}
+	if e.InitialOption != nil {
+		if err := e.InitialOption.Validate(); err != nil {
+			return fmt.Errorf("static_select: initial_option: %w", err)
+		}
+	}
+	return e.Confirm.Validate()
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (e StaticSelect) MarshalJSON() ([]byte, error) {
+	type alias StaticSelect
+	return marshalWithType(StaticSelectType, alias(e))
+}
+
+// UsersSelect is a select menu with the list of users in the workspace.
+// see. https://api.slack.com/reference/block-kit/block-elements#users_select
+type UsersSelect struct {
+	ActionID    string   `json:"action_id,omitempty"`
+	Placeholder *Text    `json:"placeholder,omitempty"`
+	InitialUser string   `json:"initial_user,omitempty"`
+	Confirm     *Confirm `json:"confirm,omitempty"`
+}
+
+// NewUsersSelect creates a users select menu.
+func NewUsersSelect(actionID, placeholder string) *UsersSelect {
+	return &UsersSelect{ActionID: actionID, Placeholder: placeholderText(placeholder)}
+}
+
+// ElementType implements the Element interface.
+func (UsersSelect) ElementType() string { return UsersSelectType }
+
+// Validate implements the Element interface.
+func (e UsersSelect) Validate() error {
+	if err := validateActionID(UsersSelectType, e.ActionID); err != nil {
+		return err
+	}
+	if err := e.Placeholder.validatePlain("users_select: placeholder", 150, false); err != nil {
+		return err
+	}
+	return e.Confirm.Validate()
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (e UsersSelect) MarshalJSON() ([]byte, error) {
+	type alias UsersSelect
+	return marshalWithType(UsersSelectType, alias(e))
+}
+
+// ConversationsSelect is a select menu with the list of conversations.
+// see. https://api.slack.com/reference/block-kit/block-elements#conversations_select
+type ConversationsSelect struct {
+	ActionID                     string   `json:"action_id,omitempty"`
+	Placeholder                  *Text    `json:"placeholder,omitempty"`
+	InitialConversation          string   `json:"initial_conversation,omitempty"`
+	DefaultToCurrentConversation bool     `json:"default_to_current_conversation,omitempty"`
+	ResponseURLEnabled           bool     `json:"response_url_enabled,omitempty"`
+	Confirm                      *Confirm `json:"confirm,omitempty"`
+}
+
+// NewConversationsSelect creates a conversations select menu.
+func NewConversationsSelect(actionID, placeholder string) *ConversationsSelect {
+	return &ConversationsSelect{ActionID: actionID, Placeholder: placeholderText(placeholder)}
+}
+
+// ElementType implements the Element interface.
+func (ConversationsSelect) ElementType() string { return ConversationsSelectType }
+
+// Validate implements the Element interface.
+func (e ConversationsSelect) Validate() error {
+	if err := validateActionID(ConversationsSelectType, e.ActionID); err != nil {
+		return err
+	}
+	if err := e.Placeholder.validatePlain("conversations_select: placeholder", 150, false); err != nil {
+		return err
+	}
+	return e.Confirm.Validate()
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (e ConversationsSelect) MarshalJSON() ([]byte, error) {
+	type alias ConversationsSelect
+	return marshalWithType(ConversationsSelectType, alias(e))
+}
+
+// ChannelsSelect is a select menu with the list of public channels.
+// see. https://api.slack.com/reference/block-kit/block-elements#channels_select
+type ChannelsSelect struct {
+	ActionID           string   `json:"action_id,omitempty"`
+	Placeholder        *Text    `json:"placeholder,omitempty"`
+	InitialChannel     string   `json:"initial_channel,omitempty"`
+	ResponseURLEnabled bool     `json:"response_url_enabled,omitempty"`
+	Confirm            *Confirm `json:"confirm,omitempty"`
+}
+
+// NewChannelsSelect creates a channels select menu.
+func NewChannelsSelect(actionID, placeholder string) *ChannelsSelect {
+	return &ChannelsSelect{ActionID: actionID, Placeholder: placeholderText(placeholder)}
+}
+
+// ElementType implements the Element interface.
+func (ChannelsSelect) ElementType() string { return ChannelsSelectType }
+
+// Validate implements the Element interface.
+func (e ChannelsSelect) Validate() error {
+	if err := validateActionID(ChannelsSelectType, e.ActionID); err != nil {
+		return err
+	}
+	if err := e.Placeholder.validatePlain("channels_select: placeholder", 150, false); err != nil {
+		return err
+	}
+	return e.Confirm.Validate()
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (e ChannelsSelect) MarshalJSON() ([]byte, error) {
+	type alias ChannelsSelect
+	return marshalWithType(ChannelsSelectType, alias(e))
+}
+
+// DatePicker is an element to select a date from a calendar.
+// see. https://api.slack.com/reference/block-kit/block-elements#datepicker
+type DatePicker struct {
+	ActionID    string   `json:"action_id,omitempty"`
+	Placeholder *Text    `json:"placeholder,omitempty"`
+	InitialDate string   `json:"initial_date,omitempty"` // YYYY-MM-DD
+	Confirm     *Confirm `json:"confirm,omitempty"`
+}
+
+// NewDatePicker creates a date picker element.
+func NewDatePicker(actionID, placeholder string) *DatePicker {
+	return &DatePicker{ActionID: actionID, Placeholder: placeholderText(placeholder)}
+}
+
+// ElementType implements the Element interface.
+func (DatePicker) ElementType() string { return DatePickerType }
+
+// Validate implements the Element interface.
+func (e DatePicker) Validate() error {
+	if err := validateActionID(DatePickerType, e.ActionID); err != nil {
+		return err
+	}
+	if err := e.Placeholder.validatePlain("datepicker: placeholder", 150, false); err != nil {
+		return err
+	}
+	if e.InitialDate != "" {
+		if _, err := time.Parse("2006-01-02", e.InitialDate); err != nil {
+			return fmt.Errorf("datepicker: initial_date must be YYYY-MM-DD: %q", e.InitialDate)
+		}
+	}
+	return e.Confirm.Validate()
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (e DatePicker) MarshalJSON() ([]byte, error) {
+	type alias DatePicker
+	return marshalWithType(DatePickerType, alias(e))
+}
+
+// Overflow is a compact menu of options.
+// see. https://api.slack.com/reference/block-kit/block-elements#overflow
+type Overflow struct {
+	ActionID string    `json:"action_id,omitempty"`
+	Options  []*Option `json:"options"`
+	Confirm  *Confirm  `json:"confirm,omitempty"`
+}
+
+// NewOverflow creates an overflow menu.
+func NewOverflow(actionID string, options ...*Option) *Overflow {
+	return &Overflow{ActionID: actionID, Options: options}
+}
+
+// ElementType implements the Element interface.
+func (Overflow) ElementType() string { return OverflowType }
+
+// Validate implements the Element interface.
+func (e Overflow) Validate() error {
+	if err := validateActionID(OverflowType, e.ActionID); err != nil {
+		return err
+	}
+	if err := validateOptions("overflow: options", e.Options, 1, 5); err != nil {
+		return err
+	}
+	return e.Confirm.Validate()
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (e Overflow) MarshalJSON() ([]byte, error) {
+	type alias Overflow
+	return marshalWithType(OverflowType, alias(e))
+}
+
+// PlainTextInput is a free-text input element.
+// see. https://api.slack.com/reference/block-kit/block-elements#input
+type PlainTextInput struct {
+	ActionID     string `json:"action_id,omitempty"`
+	Placeholder  *Text  `json:"placeholder,omitempty"`
+	InitialValue string `json:"initial_value,omitempty"`
+	Multiline    bool   `json:"multiline,omitempty"`
+	MinLength    int    `json:"min_length,omitempty"`
+	MaxLength    int    `json:"max_length,omitempty"`
+}
+
+// NewPlainTextInput creates a plain text input element.
+func NewPlainTextInput(actionID, placeholder string) *PlainTextInput {
+	return &PlainTextInput{ActionID: actionID, Placeholder: placeholderText(placeholder)}
+}
+
+// ElementType implements the Element interface.
+func (PlainTextInput) ElementType() string { return PlainTextInputType }
+
+// Validate implements the Element interface.
+func (e PlainTextInput) Validate() error {
+	if err := validateActionID(PlainTextInputType, e.ActionID); err != nil {
+		return err
+	}
+	if err := e.Placeholder.validatePlain("plain_text_input: placeholder", 150, false); err != nil {
+		return err
+	}
+	if e.MinLength < 0 || e.MinLength > 3000 {
+		return fmt.Errorf("plain_text_input: min_length out of range [0, 3000]: %d", e.MinLength)
+	}
+	if e.MaxLength < 0 || e.MaxLength > 3000 || (e.MaxLength > 0 && e.MaxLength < e.MinLength) {
+		return fmt.Errorf("plain_text_input: invalid max_length: %d", e.MaxLength)
+	}
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (e PlainTextInput) MarshalJSON() ([]byte, error) {
+	type alias PlainTextInput
+	return marshalWithType(PlainTextInputType, alias(e))
+}
+
+// Checkboxes is a group of checkboxes.
+// see. https://api.slack.com/reference/block-kit/block-elements#checkboxes
+type Checkboxes struct {
+	ActionID       string    `json:"action_id,omitempty"`
+	Options        []*Option `json:"options"`
+	InitialOptions []*Option `json:"initial_options,omitempty"`
+	Confirm        *Confirm  `json:"confirm,omitempty"`
+}
+
+// NewCheckboxes creates a checkbox group.
+func NewCheckboxes(actionID string, options ...*Option) *Checkboxes {
+	return &Checkboxes{ActionID: actionID, Options: options}
+}
+
+// ElementType implements the Element interface.
+func (Checkboxes) ElementType() string { return CheckboxesType }
+
+// Validate implements the Element interface.
+func (e Checkboxes) Validate() error {
+	if err := validateActionID(CheckboxesType, e.ActionID); err != nil {
+		return err
+	}
+	if err := validateOptions("checkboxes: options", e.Options, 1, 10); err != nil {
+		return err
+	}
+	return e.Confirm.Validate()
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (e Checkboxes) MarshalJSON() ([]byte, error) {
+	type alias Checkboxes
+	return marshalWithType(CheckboxesType, alias(e))
+}
+
+// RadioButtons is a group of radio buttons.
+// see. https://api.slack.com/reference/block-kit/block-elements#radio
+type RadioButtons struct {
+	ActionID      string    `json:"action_id,omitempty"`
+	Options       []*Option `json:"options"`
+	InitialOption *Option   `json:"initial_option,omitempty"`
+	Confirm       *Confirm  `json:"confirm,omitempty"`
+}
+
+// NewRadioButtons creates a radio button group.
+func NewRadioButtons(actionID string, options ...*Option) *RadioButtons {
+	return &RadioButtons{ActionID: actionID, Options: options}
+}
+
+// ElementType implements the Element interface.
+func (RadioButtons) ElementType() string { return RadioButtonsType }
+
+// Validate implements the Element interface.
+func (e RadioButtons) Validate() error {
+	if err := validateActionID(RadioButtonsType, e.ActionID); err != nil {
+		return err
+	}
+	if err := validateOptions("radio_buttons: options", e.Options, 1, 10); err != nil {
+		return err
+	}
+	return e.Confirm.Validate()
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (e RadioButtons) MarshalJSON() ([]byte, error) {
+	type alias RadioButtons
+	return marshalWithType(RadioButtonsType, alias(e))
+}
+
+// ImageElement is an image used in the section and context blocks.
+// see. https://api.slack.com/reference/block-kit/block-elements#image
+type ImageElement struct {
+	ImageURL string `json:"image_url"`
+	AltText  string `json:"alt_text"`
+}
+
+// NewImageElement creates an image element.
+func NewImageElement(imageURL, altText string) *ImageElement {
+	return &ImageElement{ImageURL: imageURL, AltText: altText}
+}
+
+// ElementType implements the Element interface.
+func (ImageElement) ElementType() string { return ImageElementType }
+
+// Validate implements the Element interface.
+func (e ImageElement) Validate() error {
+	if err := validateLength("image: image_url", e.ImageURL, 3000, true); err != nil {
+		return err
+	}
+	return validateLength("image: alt_text", e.AltText, 2000, true)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (e ImageElement) MarshalJSON() ([]byte, error) {
+	type alias ImageElement
+	return marshalWithType(ImageElementType, alias(e))
+}
+
+func validateActionID(typ, id string) error {
+	return validateLength(typ+": action_id", id, 255, false)
+}
+
+// placeholderText returns nil for the empty placeholder.
+func placeholderText(s string) *Text {
+	if s == "" {
+		return nil
+	}
+	return PlainText(s)
+}
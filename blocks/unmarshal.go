@@ -0,0 +1,256 @@
+package blocks
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Unknown is a block or an element of a type this package does not implement, e.g. the rich_text block
+// of the messages posted by users. It keeps the JSON as it is, so that it can be sent back unchanged.
+type Unknown struct {
+	Type string
+	Raw  json.RawMessage
+}
+
+// BlockType implements the Block interface.
+func (u Unknown) BlockType() string { return u.Type }
+
+// ElementType implements the Element interface.
+func (u Unknown) ElementType() string { return u.Type }
+
+// Validate implements the Block and the Element interfaces. The unknown blocks are validated by Slack.
+func (u Unknown) Validate() error { return nil }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (u Unknown) MarshalJSON() ([]byte, error) {
+	if len(u.Raw) == 0 {
+		return marshalWithType(u.Type, struct{}{})
+	}
+	return u.Raw, nil
+}
+
+// UnmarshalBlock decodes the JSON of a layout block by its type.
+// The block of an unknown type is decoded as Unknown.
+func UnmarshalBlock(b []byte) (Block, error) {
+	typ, err := peekType(b)
+	if err != nil {
+		return nil, err
+	}
+	var ret Block
+	switch typ {
+	case SectionType:
+		ret = &Section{}
+	case HeaderType:
+		ret = &Header{}
+	case DividerType:
+		ret = &Divider{}
+	case ContextType:
+		ret = &Context{}
+	case ActionsType:
+		ret = &Actions{}
+	case ImageType:
+		ret = &Image{}
+	case InputType:
+		ret = &Input{}
+	default:
+		return &Unknown{Type: typ, Raw: append(json.RawMessage(nil), b...)}, nil
+	}
+	if err := json.Unmarshal(b, ret); err != nil {
+		return nil, fmt.Errorf("%s block decode error: %w", typ, err)
+	}
+	return ret, nil
+}
+
+// UnmarshalElement decodes the JSON of a block element or a text object by its type.
+// The element of an unknown type is decoded as Unknown.
+func UnmarshalElement(b []byte) (Element, error) {
+	typ, err := peekType(b)
+	if err != nil {
+		return nil, err
+	}
+	var ret Element
+	switch typ {
+	case PlainTextType, MarkdownType:
+		ret = &Text{}
+	case ButtonType:
+		ret = &Button{}
+	case StaticSelectType:
+		ret = &StaticSelect{}
+	case UsersSelectType:
+		ret = &UsersSelect{}
+	case ConversationsSelectType:
+		ret = &ConversationsSelect{}
+	case ChannelsSelectType:
+		ret = &ChannelsSelect{}
+	case DatePickerType:
+		ret = &DatePicker{}
+	case OverflowType:
+		ret = &Overflow{}
+	case PlainTextInputType:
+		ret = &PlainTextInput{}
+	case CheckboxesType:
+		ret = &Checkboxes{}
+	case RadioButtonsType:
+		ret = &RadioButtons{}
+	case ImageElementType:
+		ret = &ImageElement{}
+	default:
+		return &Unknown{Type: typ, Raw: append(json.RawMessage(nil), b...)}, nil
+	}
+	if err := json.Unmarshal(b, ret); err != nil {
+		return nil, fmt.Errorf("%s element decode error: %w", typ, err)
+	}
+	return ret, nil
+}
+
+func peekType(b []byte) (string, error) {
+	var v struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return "", fmt.Errorf("block decode error: %w", err)
+	}
+	if v.Type == "" {
+		return "", fmt.Errorf("block decode error: type required")
+	}
+	return v.Type, nil
+}
+
+func unmarshalElements(raws []json.RawMessage) ([]Element, error) {
+	if raws == nil {
+		return nil, nil
+	}
+	ret := make([]Element, 0, len(raws))
+	for i, v := range raws {
+		e, err := UnmarshalElement(v)
+		if err != nil {
+			return nil, fmt.Errorf("elements[%d]: %w", i, err)
+		}
+		ret = append(ret, e)
+	}
+	return ret, nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (bs *Blocks) UnmarshalJSON(b []byte) error {
+	var raws []json.RawMessage
+	if err := json.Unmarshal(b, &raws); err != nil {
+		return err
+	}
+	if raws == nil {
+		*bs = nil
+		return nil
+	}
+	ret := make(Blocks, 0, len(raws))
+	for i, v := range raws {
+		block, err := UnmarshalBlock(v)
+		if err != nil {
+			return fmt.Errorf("blocks[%d]: %w", i, err)
+		}
+		ret = append(ret, block)
+	}
+	*bs = ret
+	return nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (b *Section) UnmarshalJSON(data []byte) error {
+	type alias Section
+	v := struct {
+		*alias
+		Accessory json.RawMessage `json:"accessory"`
+	}{
+		alias: (*alias)(b),
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if len(v.Accessory) == 0 || string(v.Accessory) == "null" {
+		return nil
+	}
+	e, err := UnmarshalElement(v.Accessory)
+	if err != nil {
+		return fmt.Errorf("section: accessory: %w", err)
+	}
+	b.Accessory = e
+	return nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (b *Context) UnmarshalJSON(data []byte) error {
+	type alias Context
+	v := struct {
+		*alias
+		Elements []json.RawMessage `json:"elements"`
+	}{
+		alias: (*alias)(b),
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	es, err := unmarshalElements(v.Elements)
+	if err != nil {
+		return fmt.Errorf("context: %w", err)
+	}
+	b.Elements = es
+	return nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (b *Actions) UnmarshalJSON(data []byte) error {
+	type alias Actions
+	v := struct {
+		*alias
+		Elements []json.RawMessage `json:"elements"`
+	}{
+		alias: (*alias)(b),
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	es, err := unmarshalElements(v.Elements)
+	if err != nil {
+		return fmt.Errorf("actions: %w", err)
+	}
+	b.Elements = es
+	return nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (b *Input) UnmarshalJSON(data []byte) error {
+	type alias Input
+	v := struct {
+		*alias
+		Element json.RawMessage `json:"element"`
+	}{
+		alias: (*alias)(b),
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if len(v.Element) == 0 || string(v.Element) == "null" {
+		return nil
+	}
+	e, err := UnmarshalElement(v.Element)
+	if err != nil {
+		return fmt.Errorf("input: element: %w", err)
+	}
+	b.Element = e
+	return nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (v *View) UnmarshalJSON(data []byte) error {
+	type alias View
+	w := struct {
+		*alias
+		Blocks Blocks `json:"blocks"`
+	}{
+		alias: (*alias)(v),
+	}
+	if err := json.Unmarshal(data, &w); err != nil {
+		return err
+	}
+	v.Blocks = w.Blocks
+	return nil
+}
@@ -0,0 +1,204 @@
+package blocks_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/ikawaha/slackbot/blocks"
+)
+
+func TestUnmarshalBlock(t *testing.T) {
+	confirm := blocks.NewConfirm("title", "sure?", "yes", "no")
+	tests := []struct {
+		name  string
+		block blocks.Block
+	}{
+		{name: "section", block: &blocks.Section{
+			BlockID:   "b1",
+			Text:      blocks.Markdown("*hi*"),
+			Fields:    []*blocks.Text{blocks.PlainText("a"), blocks.Markdown("b")},
+			Accessory: &blocks.Button{ActionID: "a1", Text: blocks.PlainText("ok"), Value: "v", Style: "primary", Confirm: confirm},
+		}},
+		{name: "section without accessory", block: blocks.NewSection(blocks.Markdown("text"))},
+		{name: "header", block: &blocks.Header{BlockID: "b2", Text: blocks.PlainText("title")}},
+		{name: "divider", block: &blocks.Divider{BlockID: "b3"}},
+		{name: "context", block: &blocks.Context{BlockID: "b4", Elements: []blocks.Element{
+			blocks.Markdown("by"),
+			blocks.NewImageElement("https://example.com/a.png", "avatar"),
+		}}},
+		{name: "actions", block: &blocks.Actions{BlockID: "b5", Elements: []blocks.Element{
+			blocks.NewButton("a2", "click", "v"),
+			&blocks.StaticSelect{ActionID: "a3", Placeholder: blocks.PlainText("pick"), Options: []*blocks.Option{blocks.NewOption("one", "1")}, InitialOption: blocks.NewOption("one", "1")},
+			&blocks.StaticSelect{ActionID: "a4", OptionGroups: []*blocks.OptionGroup{blocks.NewOptionGroup("group", blocks.NewOption("two", "2"))}},
+			&blocks.UsersSelect{ActionID: "a5", Placeholder: blocks.PlainText("user"), InitialUser: "U1"},
+			&blocks.ConversationsSelect{ActionID: "a6", InitialConversation: "C1", DefaultToCurrentConversation: true, ResponseURLEnabled: true},
+			&blocks.ChannelsSelect{ActionID: "a7", InitialChannel: "C1", ResponseURLEnabled: true},
+			&blocks.DatePicker{ActionID: "a8", InitialDate: "2026-01-02", Confirm: confirm},
+			&blocks.Overflow{ActionID: "a9", Options: []*blocks.Option{{Text: blocks.PlainText("link"), Value: "l", URL: "https://example.com"}}},
+			&blocks.Checkboxes{ActionID: "a10", Options: []*blocks.Option{blocks.NewOption("c", "c")}, InitialOptions: []*blocks.Option{blocks.NewOption("c", "c")}},
+			&blocks.RadioButtons{ActionID: "a11", Options: []*blocks.Option{{Text: blocks.Markdown("r"), Value: "r", Description: blocks.PlainText("desc")}}},
+		}}},
+		{name: "image", block: &blocks.Image{BlockID: "b6", ImageURL: "https://example.com/a.png", AltText: "alt", Title: blocks.PlainText("title")}},
+		{name: "input", block: &blocks.Input{
+			BlockID:        "b7",
+			Label:          blocks.PlainText("name"),
+			Element:        &blocks.PlainTextInput{ActionID: "a12", Placeholder: blocks.PlainText("type"), InitialValue: "x", Multiline: true, MinLength: 1, MaxLength: 10},
+			DispatchAction: true,
+			Hint:           blocks.PlainText("hint"),
+			Optional:       true,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(tt.block)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got, err := blocks.UnmarshalBlock(b)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.block) {
+				t.Errorf("want %#v, got %#v", tt.block, got)
+			}
+			again, err := json.Marshal(got)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(again) != string(b) {
+				t.Errorf("want %s, got %s", b, again)
+			}
+		})
+	}
+}
+
+func TestUnmarshalElement(t *testing.T) {
+	tests := []struct {
+		name    string
+		element blocks.Element
+	}{
+		{name: "plain_text", element: &blocks.Text{Type: blocks.PlainTextType, Text: "a", Emoji: true}},
+		{name: "mrkdwn", element: &blocks.Text{Type: blocks.MarkdownType, Text: "*a*", Verbatim: true}},
+		{name: "button", element: &blocks.Button{ActionID: "a", Text: blocks.PlainText("b"), URL: "https://example.com", AccessibilityLabel: "label"}},
+		{name: "static_select", element: blocks.NewStaticSelect("a", "pick", blocks.NewOption("one", "1"))},
+		{name: "users_select", element: blocks.NewUsersSelect("a", "user")},
+		{name: "conversations_select", element: blocks.NewConversationsSelect("a", "conversation")},
+		{name: "channels_select", element: blocks.NewChannelsSelect("a", "channel")},
+		{name: "datepicker", element: blocks.NewDatePicker("a", "date")},
+		{name: "overflow", element: blocks.NewOverflow("a", blocks.NewOption("one", "1"))},
+		{name: "plain_text_input", element: blocks.NewPlainTextInput("a", "type")},
+		{name: "checkboxes", element: blocks.NewCheckboxes("a", blocks.NewOption("one", "1"))},
+		{name: "radio_buttons", element: blocks.NewRadioButtons("a", blocks.NewOption("one", "1"))},
+		{name: "image", element: blocks.NewImageElement("https://example.com/a.png", "alt")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(tt.element)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got, err := blocks.UnmarshalElement(b)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.ElementType() != tt.name {
+				t.Errorf("want %s, got %s", tt.name, got.ElementType())
+			}
+			if !reflect.DeepEqual(got, tt.element) {
+				t.Errorf("want %#v, got %#v", tt.element, got)
+			}
+			again, err := json.Marshal(got)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(again) != string(b) {
+				t.Errorf("want %s, got %s", b, again)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_Unknown(t *testing.T) {
+	tests := []struct {
+		name      string
+		unmarshal func([]byte) (interface{}, error)
+		in        string
+		typ       string
+	}{
+		{
+			name:      "block",
+			unmarshal: func(b []byte) (interface{}, error) { return blocks.UnmarshalBlock(b) },
+			in:        `{"type":"rich_text","block_id":"x","elements":[{"type":"rich_text_section","elements":[{"type":"text","text":"hi","style":{"bold":true}}]}]}`,
+			typ:       "rich_text",
+		},
+		{
+			name:      "element",
+			unmarshal: func(b []byte) (interface{}, error) { return blocks.UnmarshalElement(b) },
+			in:        `{"type":"timepicker","action_id":"a","initial_time":"09:00","timezone":"Asia/Tokyo"}`,
+			typ:       "timepicker",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.unmarshal([]byte(tt.in))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			u, ok := got.(*blocks.Unknown)
+			if !ok {
+				t.Fatalf("want Unknown, got %T", got)
+			}
+			if u.Type != tt.typ || string(u.Raw) != tt.in {
+				t.Errorf("want the raw JSON of %s, got %+v", tt.typ, u)
+			}
+			b, err := json.Marshal(u)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(b) != tt.in {
+				t.Errorf("want %s, got %s", tt.in, b)
+			}
+		})
+	}
+
+	// the unknown blocks and elements nested in the known ones are kept as they are.
+	in := `[{"type":"section","text":{"type":"mrkdwn","text":"when?"},"accessory":{"type":"timepicker","action_id":"t"}},` +
+		`{"type":"rich_text","elements":[]},` +
+		`{"type":"actions","elements":[{"type":"workflow_button","text":{"type":"plain_text","text":"run"}}]}]`
+	var bs blocks.Blocks
+	if err := json.Unmarshal([]byte(in), &bs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := bs[0].(*blocks.Section).Accessory.(*blocks.Unknown); !ok {
+		t.Errorf("want the unknown accessory, got %T", bs[0].(*blocks.Section).Accessory)
+	}
+	b, err := json.Marshal(bs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(b) != in {
+		t.Errorf("want %s, got %s", in, b)
+	}
+}
+
+func TestUnmarshalBlock_Error(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{name: "invalid json", in: `{`},
+		{name: "no type", in: `{"block_id":"x"}`},
+		{name: "not an object", in: `"section"`},
+		{name: "invalid field", in: `{"type":"header","text":"title"}`},
+		{name: "invalid element", in: `{"type":"actions","elements":[{"text":"no type"}]}`},
+		{name: "invalid accessory", in: `{"type":"section","accessory":{"type":"button","text":1}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := blocks.UnmarshalBlock([]byte(tt.in)); err == nil {
+				t.Error("want error")
+			}
+		})
+	}
+}
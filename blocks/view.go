@@ -0,0 +1,87 @@
+package blocks
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// View types.
+const (
+	ModalType = "modal"
+	HomeType  = "home"
+)
+
+// View is the modal or home tab view.
+// see. https://api.slack.com/reference/surfaces/views
+type View struct {
+	Type            string  `json:"type"`
+	Title           *Text   `json:"title,omitempty"`
+	Blocks          []Block `json:"blocks"`
+	Close           *Text   `json:"close,omitempty"`
+	Submit          *Text   `json:"submit,omitempty"`
+	PrivateMetadata string  `json:"private_metadata,omitempty"`
+	CallbackID      string  `json:"callback_id,omitempty"`
+	ClearOnClose    bool    `json:"clear_on_close,omitempty"`
+	NotifyOnClose   bool    `json:"notify_on_close,omitempty"`
+	ExternalID      string  `json:"external_id,omitempty"`
+	SubmitDisabled  bool    `json:"submit_disabled,omitempty"`
+}
+
+// NewModal creates a modal view with the title.
+func NewModal(title string, blocks ...Block) *View {
+	return &View{Type: ModalType, Title: PlainText(title), Blocks: blocks}
+}
+
+// NewHome creates a home tab view.
+func NewHome(blocks ...Block) *View {
+	return &View{Type: HomeType, Blocks: blocks}
+}
+
+// Validate validates the view.
+func (v View) Validate() error {
+	switch v.Type {
+	case ModalType:
+		if err := v.Title.validatePlain("view: title", 24, true); err != nil {
+			return err
+		}
+		if err := v.Close.validatePlain("view: close", 24, false); err != nil {
+			return err
+		}
+		if err := v.Submit.validatePlain("view: submit", 24, false); err != nil {
+			return err
+		}
+		if v.Submit == nil {
+			for _, b := range v.Blocks {
+				if b != nil && b.BlockType() == InputType {
+					return fmt.Errorf("view: submit required for a modal with input blocks")
+				}
+			}
+		}
+	case HomeType:
+	default:
+		return fmt.Errorf("view: unknown type: %q", v.Type)
+	}
+	if err := validateLength("view: private_metadata", v.PrivateMetadata, 3000, false); err != nil {
+		return err
+	}
+	if err := validateLength("view: callback_id", v.CallbackID, 255, false); err != nil {
+		return err
+	}
+	if err := validateLength("view: external_id", v.ExternalID, 255, false); err != nil {
+		return err
+	}
+	if err := validateBlocks(v.Blocks, MaxViewBlocks); err != nil {
+		return fmt.Errorf("view: %w", err)
+	}
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// The blocks are always marshaled as an array, as Slack rejects the null blocks.
+func (v View) MarshalJSON() ([]byte, error) {
+	type alias View
+	if v.Blocks == nil {
+		v.Blocks = []Block{}
+	}
+	return json.Marshal(alias(v))
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"log"
 	"regexp"
 	"strings"
+	"time"
 
+	"github.com/ikawaha/slackbot/blocks"
 	"github.com/ikawaha/slackbot/socketmode"
 	"github.com/ikawaha/slackbot/webapi"
 )
@@ -17,6 +20,7 @@ type Client struct {
 	ID               string
 	webAPIClient     *webapi.Client
 	socketModeClient *socketmode.Client
+	scheduler        *scheduler
 }
 
 type (
@@ -25,6 +29,45 @@ type (
 
 	// User is an alias type of the web api user.
 	User = webapi.User
+
+	// UserProfile is an alias type of the web api user profile.
+	UserProfile = webapi.UserProfile
+
+	// UserDirectory is an alias type of the web api user cache.
+	UserDirectory = webapi.UserDirectory
+
+	// ChannelDirectory is an alias type of the web api channel cache.
+	ChannelDirectory = webapi.ChannelDirectory
+
+	// CacheStore is an alias type of the web api cache store.
+	CacheStore = webapi.CacheStore
+
+	// MemoryStore is an alias type of the web api cache store in memory.
+	MemoryStore = webapi.MemoryStore
+
+	// FileStore is an alias type of the web api cache store in files.
+	FileStore = webapi.FileStore
+
+	// APIError is an alias type of the web api error.
+	APIError = webapi.APIError
+
+	// RateLimitStats is an alias type of the web api rate limit statistics.
+	RateLimitStats = webapi.RateLimitStats
+
+	// RetryPolicy is an alias type of the web api retry policy.
+	RetryPolicy = webapi.RetryPolicy
+
+	// ReconnectPolicy is an alias type of the socket mode reconnect policy.
+	ReconnectPolicy = socketmode.ReconnectPolicy
+
+	// ReconnectHook is an alias type of the socket mode reconnect hook.
+	ReconnectHook = socketmode.ReconnectHook
+
+	// DisconnectHook is an alias type of the socket mode disconnect hook.
+	DisconnectHook = socketmode.DisconnectHook
+
+	// DisconnectError is an alias type of the socket mode disconnect error.
+	DisconnectError = socketmode.DisconnectError
 )
 
 // New creates a slack bot from app-level token and API token.
@@ -42,11 +85,19 @@ func New(appLevelToken, apiToken string, opts ...Option) (*Client, error) {
 	}
 	s, err := socketmode.New(appLevelToken, c.socketModeClientOptions...)
 	if err != nil {
+		_ = a.Close()
 		return nil, err
 	}
 	ret := Client{
 		webAPIClient:     a,
 		socketModeClient: s,
+		scheduler: newScheduler(func(userID string) (*time.Location, bool) {
+			u, ok := a.User(userID)
+			if !ok {
+				return nil, false
+			}
+			return u.Location(), true
+		}),
 	}
 	if c.searchBotID {
 		id := a.UserID(c.botName)
@@ -59,30 +110,191 @@ func New(appLevelToken, apiToken string, opts ...Option) (*Client, error) {
 	return &ret, nil
 }
 
+// ErrCacheMiss is returned when the cache store has no entries of the kind.
+var ErrCacheMiss = webapi.ErrCacheMiss
+
+// NewMemoryStore creates an empty cache store in memory.
+func NewMemoryStore() *MemoryStore {
+	return webapi.NewMemoryStore()
+}
+
+// NewFileStore creates the cache store which saves the caches as JSON files in the directory.
+func NewFileStore(dir string) (*FileStore, error) {
+	return webapi.NewFileStore(dir)
+}
+
 var (
 	metaTag     = regexp.MustCompile(`<.*?>`)
 	parentheses = strings.NewReplacer("&lt;", "<", "&gt;", ">")
 )
 
 // ReceiveMessage receives a message and passes it to a handler for processing.
+// The client's caches are updated by the event before the handler is called.
 func (c Client) ReceiveMessage(ctx context.Context, handler func(ctx context.Context, e *Event) error) error {
-	return c.socketModeClient.ReceiveMessage(ctx, handler)
+	return c.socketModeClient.ReceiveMessage(ctx, func(ctx context.Context, e *Event) error {
+		c.observe(e)
+		return handler(ctx, e)
+	})
+}
+
+// observe updates the client's caches by the event.
+func (c Client) observe(e *Event) {
+	switch EventType(e.Type) {
+	case UserChange, TeamJoin:
+		if e.User != nil {
+			c.webAPIClient.UserDirectory().Put(*e.User)
+		}
+	case ChannelCreated:
+		if e.Conversation != nil {
+			ch := *e.Conversation
+			ch.IsChannel = true
+			c.webAPIClient.ChannelDirectory().Put(ch)
+		}
+	case ChannelRename:
+		if e.Conversation != nil {
+			c.webAPIClient.ChannelDirectory().Rename(e.Conversation.ID, e.Conversation.Name)
+		}
+	case ChannelArchive:
+		c.webAPIClient.ChannelDirectory().SetArchived(e.Channel, true)
+	case ChannelUnarchive:
+		c.webAPIClient.ChannelDirectory().SetArchived(e.Channel, false)
+	case ChannelDeleted:
+		c.webAPIClient.ChannelDirectory().Remove(e.Channel)
+	}
+}
+
+// Run receives messages and passes them to a handler until the context is canceled.
+// Errors returned by the handler are logged and do not stop the loop.
+// The scheduled jobs run while Run is running. If RunScheduler is already running, Run logs it and leaves the jobs to it.
+// Run returns nil when the context is canceled, otherwise the error that made it stop receiving.
+func (c Client) Run(ctx context.Context, handler HandlerFunc) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		if err := c.scheduler.run(ctx); err != nil {
+			log.Printf("scheduler: %v", err)
+		}
+	}()
+	h := func(ctx context.Context, e *Event) error {
+		c.observe(e)
+		if err := handler(ctx, e); err != nil {
+			log.Printf("handler error: event_type: %s, %v", e.Type, err)
+		}
+		return nil
+	}
+	for {
+		if err := c.socketModeClient.ReceiveMessage(ctx, h); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+	}
 }
 
 // PostMessage sends a message to the Slack channel.
-func (c Client) PostMessage(ctx context.Context, channelID, msg string) error {
-	_, err := c.webAPIClient.PostMessage(ctx, channelID, msg)
+// The channel is the ID, or the name which starts with "#", e.g. "#general".
+func (c Client) PostMessage(ctx context.Context, channel, msg string, opts ...MessageOption) error {
+	_, err := c.webAPIClient.PostMessage(ctx, channel, msg, opts...)
+	return err
+}
+
+// PostMessageWithResponse sends a message to the Slack channel and returns the response,
+// which has the timestamp of the posted message.
+func (c Client) PostMessageWithResponse(ctx context.Context, channelID, msg string, opts ...MessageOption) (*MessageResponse, error) {
+	return c.webAPIClient.PostMessage(ctx, channelID, msg, opts...)
+}
+
+// Reply posts a message where the event happened: in the thread if the event is a message
+// in a thread, otherwise in the channel.
+func (c Client) Reply(ctx context.Context, e *Event, msg string, opts ...MessageOption) error {
+	if e.InThread() {
+		opts = append([]MessageOption{ThreadTS(e.ThreadTS)}, opts...)
+	}
+	return c.PostMessage(ctx, e.Channel, msg, opts...)
+}
+
+// ReplyInThread posts a message in the thread of the event.
+// If the event is not in a thread, the message starts a new thread on the message of the event.
+func (c Client) ReplyInThread(ctx context.Context, e *Event, msg string, opts ...MessageOption) error {
+	ts := e.ReplyThreadTS()
+	if ts == "" {
+		return fmt.Errorf("no message to reply in thread: event_type: %s", e.Type)
+	}
+	opts = append([]MessageOption{ThreadTS(ts)}, opts...)
+	return c.PostMessage(ctx, e.Channel, msg, opts...)
+}
+
+// ReplyEphemeral posts a message visible only to the user of the event where the event happened:
+// in the thread if the event is a message in a thread, otherwise in the channel.
+func (c Client) ReplyEphemeral(ctx context.Context, e *Event, msg string, opts ...MessageOption) error {
+	if e.InThread() {
+		opts = append([]MessageOption{ThreadTS(e.ThreadTS)}, opts...)
+	}
+	return c.PostEphemeral(ctx, e.Channel, e.UserID, msg, opts...)
+}
+
+// UpdateMessage updates the message of the timestamp, which the bot posted, e.g. the timestamp of
+// the response of PostMessageWithResponse.
+// see. https://api.slack.com/methods/chat.update
+func (c Client) UpdateMessage(ctx context.Context, channel, ts, msg string, opts ...MessageOption) error {
+	_, err := c.webAPIClient.UpdateMessage(ctx, channel, ts, msg, opts...)
+	return err
+}
+
+// DeleteMessage deletes the message of the timestamp, which the bot posted.
+// see. https://api.slack.com/methods/chat.delete
+func (c Client) DeleteMessage(ctx context.Context, channel, ts string) error {
+	return c.webAPIClient.DeleteMessage(ctx, channel, ts)
+}
+
+// PostEphemeral sends the message visible only to the user in the channel.
+// see. https://api.slack.com/methods/chat.postEphemeral
+func (c Client) PostEphemeral(ctx context.Context, channel, userID, msg string, opts ...MessageOption) error {
+	_, err := c.webAPIClient.PostEphemeral(ctx, channel, userID, msg, opts...)
+	return err
+}
+
+// MeMessage sends the /me message, which is displayed in italics, to the channel.
+// see. https://api.slack.com/methods/chat.meMessage
+func (c Client) MeMessage(ctx context.Context, channel, msg string) error {
+	_, err := c.webAPIClient.MeMessage(ctx, channel, msg)
 	return err
 }
 
 // RespondToCommand responds to the Slack command.
-func (c Client) RespondToCommand(ctx context.Context, responseURL string, msg string, visible bool) error {
-	return c.webAPIClient.RespondToCommand(ctx, responseURL, msg, visible)
+func (c Client) RespondToCommand(ctx context.Context, responseURL string, msg string, visible bool, opts ...MessageOption) error {
+	return c.webAPIClient.RespondToCommand(ctx, responseURL, msg, visible, opts...)
+}
+
+// OpenView opens the modal view for the user who triggered the interaction.
+// see. https://api.slack.com/methods/views.open
+func (c Client) OpenView(ctx context.Context, triggerID string, view *blocks.View) error {
+	_, err := c.webAPIClient.OpenView(ctx, triggerID, view)
+	return err
+}
+
+// PushView pushes the modal view onto the stack of the root view.
+// see. https://api.slack.com/methods/views.push
+func (c Client) PushView(ctx context.Context, triggerID string, view *blocks.View) error {
+	_, err := c.webAPIClient.PushView(ctx, triggerID, view)
+	return err
+}
+
+// UpdateView updates the existing view identified by the view ID.
+// see. https://api.slack.com/methods/views.update
+func (c Client) UpdateView(ctx context.Context, viewID, hash string, view *blocks.View) error {
+	_, err := c.webAPIClient.UpdateView(ctx, viewID, hash, view)
+	return err
 }
 
 // PlainMessageText resolves meta tags of the message text and return it.
+// The user mentions are resolved to "@name" and the channel references to "#name".
 func (c Client) PlainMessageText(msg string) string {
 	txt := metaTag.ReplaceAllStringFunc(msg, func(s string) string {
+		if strings.HasPrefix(s, "<#") {
+			return c.channelReference(s)
+		}
 		var id string
 		for i := 0; i < len(s)-2; i++ {
 			if s[i] == '@' {
@@ -101,6 +313,21 @@ func (c Client) PlainMessageText(msg string) string {
 	return parentheses.Replace(txt)
 }
 
+// channelReference resolves the channel reference, <#C123> or <#C123|name>, to "#name".
+func (c Client) channelReference(s string) string {
+	id, name := s[2:len(s)-1], ""
+	if i := strings.Index(id, "|"); i >= 0 {
+		id, name = id[:i], id[i+1:]
+	}
+	if v := c.webAPIClient.ChannelDirectory().Name(id); v != "" {
+		return "#" + v
+	}
+	if name != "" {
+		return "#" + name
+	}
+	return "#" + id
+}
+
 // UploadImage uploads an image by files.upload API.
 // see. https://api.slack.com/methods/files.upload
 func (c Client) UploadImage(ctx context.Context, channels []string, title, fileName, fileType, comment string, img io.Reader) error {
@@ -108,8 +335,14 @@ func (c Client) UploadImage(ctx context.Context, channels []string, title, fileN
 }
 
 // Close implements the io.Closer interface.
+// The background refreshes of the caches are stopped, and the caches are saved to the cache store before closing.
 func (c *Client) Close() error {
-	return c.socketModeClient.Close()
+	_ = c.webAPIClient.Close()
+	saveErr := c.webAPIClient.SaveCache(context.TODO())
+	if err := c.socketModeClient.Close(); err != nil {
+		return err
+	}
+	return saveErr
 }
 
 // UsersList lists all users in a Slack team.
@@ -123,12 +356,59 @@ func (c Client) Users(ctx context.Context) (map[string]User, error) {
 	return c.webAPIClient.Users(ctx)
 }
 
+// UsersInfo gets the information about the user.
+// see. https://api.slack.com/methods/users.info
+func (c Client) UsersInfo(ctx context.Context, id string) (*User, error) {
+	return c.webAPIClient.UsersInfo(ctx, id)
+}
+
+// UserDirectory returns the client's user cache, which looks up the users by the name, the display name or the email.
+func (c Client) UserDirectory() *UserDirectory {
+	return c.webAPIClient.UserDirectory()
+}
+
+// ChannelDirectory returns the client's channel cache, which resolves the channel names to the IDs and vice versa.
+func (c Client) ChannelDirectory() *ChannelDirectory {
+	return c.webAPIClient.ChannelDirectory()
+}
+
+// RefreshChannelsCache updates the client's cached channels.
+func (c *Client) RefreshChannelsCache(ctx context.Context) error {
+	return c.webAPIClient.RefreshChannelsCache(ctx)
+}
+
+// ChannelID returns the ID of the channel. The channel is the ID, or the name which starts with "#".
+func (c Client) ChannelID(ctx context.Context, channel string) (string, error) {
+	return c.webAPIClient.ChannelDirectory().Resolve(ctx, channel)
+}
+
 // RefreshUsersCache updates the client's cached user map.
 func (c *Client) RefreshUsersCache(ctx context.Context) error {
 	return c.webAPIClient.RefreshUsersCache(ctx)
 }
 
+// Call calls any Web API method with the parameters and decodes the response into the result.
+// The params is sent as a form if it is url.Values, otherwise as a JSON body.
+// see. https://api.slack.com/methods
+func (c Client) Call(ctx context.Context, method string, params, result interface{}) error {
+	return c.webAPIClient.Call(ctx, method, params, result)
+}
+
+// RateLimitStats returns the statistics of the time the Web API calls waited for the rate limits.
+func (c Client) RateLimitStats() RateLimitStats {
+	return c.webAPIClient.RateLimitStats()
+}
+
 // User returns the user corresponding to user ID from the client's user cache.
+// It only reads the cache; use GetUser to look up the user who is not cached yet.
 func (c *Client) User(id string) (User, bool) {
 	return c.webAPIClient.User(id)
 }
+
+// GetUser returns the user corresponding to user ID. If the user is not cached,
+// GetUser looks up it by users.info and caches it.
+// see. https://api.slack.com/methods/users.info
+// required scopes: `users:read`
+func (c *Client) GetUser(ctx context.Context, id string) (User, error) {
+	return c.webAPIClient.GetUser(ctx, id)
+}
@@ -0,0 +1,211 @@
+package slackbot_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ikawaha/slackbot"
+	"github.com/ikawaha/slackbot/slacktest"
+	"github.com/ikawaha/slackbot/socketmode"
+	"github.com/ikawaha/slackbot/webapi"
+)
+
+func TestClient_Run(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	srv.AddUser(webapi.User{ID: "U1", Name: "alice"})
+	srv.AddChannel(webapi.Channel{ID: "C1", Name: "general"}, "U1")
+	bot, err := slackbot.New("xapp-token", "xoxb-token",
+		slackbot.SetBaseURL(srv.URL()),
+		slackbot.Connections(2),
+		slackbot.SetReconnectPolicy(slackbot.ReconnectPolicy{MaxAttempts: 3, BaseDelay: 10 * time.Millisecond, MaxDelay: 100 * time.Millisecond}),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer bot.Close()
+
+	r := slackbot.NewRouter()
+	r.Handle(slackbot.Message, func(ctx context.Context, e *slackbot.Event) error {
+		u, err := bot.GetUser(ctx, e.UserID)
+		if err != nil {
+			return err
+		}
+		return bot.ReplyInThread(ctx, e, "hi "+u.Name+": "+e.Text)
+	})
+	r.Handle(slackbot.SlashCommand, func(ctx context.Context, e *slackbot.Event) error {
+		if e.Text == "ack" {
+			return e.Ack(map[string]string{"text": "acked"})
+		}
+		return bot.RespondToCommand(ctx, e.ResponseURL, "echo: "+e.Text, true)
+	})
+	r.HandleDefault(func(ctx context.Context, e *slackbot.Event) error {
+		return bot.PostMessage(ctx, "#general", "default: "+string(e.Type))
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- bot.Run(ctx, r.HandleEvent) }()
+
+	wctx, wcancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer wcancel()
+
+	// a message is routed to the message handler, which replies in the thread.
+	if _, err := srv.SendEvent(socketmode.Event{Type: "message", Channel: "C1", UserID: "U1", Text: "hello", TS: "1.000001"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ms, err := srv.WaitPostedMessages(wctx, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m := ms[0]; m.Channel != "C1" || m.ThreadTS != "1.000001" || m.Text != "hi alice: hello" {
+		t.Errorf("unexpected message: %+v", m)
+	}
+
+	// a slash command acknowledged with a response payload.
+	id, err := srv.SendSlashCommand(slacktest.SlashCommand{Command: "/echo", Text: "ack", UserID: "U1", ChannelID: "C1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ack, err := srv.WaitAck(wctx, id)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var payload map[string]string
+	if err := json.Unmarshal(ack.Payload, &payload); err != nil || payload["text"] != "acked" {
+		t.Errorf("unexpected payload: %s, %v", ack.Payload, err)
+	}
+
+	// a slash command responded to the response URL.
+	if _, err := srv.SendSlashCommand(slacktest.SlashCommand{Command: "/echo", Text: "hey", UserID: "U1", ChannelID: "C1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rs, err := srv.WaitCommandResponses(wctx, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rs[0].Text != "echo: hey" || rs[0].ResponseType != "in_channel" {
+		t.Errorf("unexpected command response: %+v", rs[0])
+	}
+
+	// an event without a handler goes to the default handler.
+	if _, err := srv.SendEvent(socketmode.Event{Type: "reaction_added", UserID: "U1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ms, err = srv.WaitPostedMessages(wctx, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m := ms[1]; m.Channel != "C1" || m.Text != "default: reaction_added" {
+		t.Errorf("unexpected message: %+v", m)
+	}
+
+	// Run keeps receiving after the connections are lost.
+	srv.CloseConnections()
+	if err := srv.WaitConnects(wctx, 4, 2); err != nil {
+		t.Fatalf("want 4 connects and 2 open connections, got %d and %d", srv.Connects(), srv.NumConnections())
+	}
+	if _, err := srv.SendEvent(socketmode.Event{Type: "message", Channel: "C1", UserID: "U1", Text: "again", TS: "1.000003", ThreadTS: "1.000001"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ms, err = srv.WaitPostedMessages(wctx, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m := ms[2]; m.ThreadTS != "1.000001" || m.Text != "hi alice: again" {
+		t.Errorf("unexpected message: %+v", m)
+	}
+
+	// Run stops when the socket mode is turned off.
+	if err := srv.SendDisconnect(slackbot.DisconnectLinkDisabled); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	select {
+	case err := <-done:
+		if !errors.Is(err, slackbot.ErrLinkDisabled) {
+			t.Errorf("want ErrLinkDisabled, got %v", err)
+		}
+	case <-wctx.Done():
+		t.Fatal("Run did not stop on link_disabled")
+	}
+}
+
+func TestClient_Run_Canceled(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	bot, err := slackbot.New("xapp-token", "xoxb-token", slackbot.SetBaseURL(srv.URL()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer bot.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- bot.Run(ctx, slackbot.NewRouter().HandleEvent) }()
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("want nil on cancel, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not stop on cancel")
+	}
+}
+
+func TestClient_Run_UserEvents(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	srv.AddUser(webapi.User{ID: "U1", Name: "alice"})
+	bot, err := slackbot.New("xapp-token", "xoxb-token", slackbot.SetBaseURL(srv.URL()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer bot.Close()
+	received := make(chan *slackbot.Event, 1)
+	handler := func(ctx context.Context, e *slackbot.Event) error {
+		received <- e
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	go func() { _ = bot.Run(ctx, handler) }()
+
+	tests := []struct {
+		name  string
+		event slackbot.EventType
+		user  webapi.User
+	}{
+		{name: "user_change", event: slackbot.UserChange, user: webapi.User{ID: "U1", Name: "alice", Profile: webapi.UserProfile{DisplayName: "Ally"}}},
+		{name: "team_join", event: slackbot.TeamJoin, user: webapi.User{ID: "U2", Name: "bob", Profile: webapi.UserProfile{Email: "bob@example.com"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := srv.SendUserEvent(tt.event, tt.user); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			select {
+			case e := <-received:
+				if slackbot.EventType(e.Type) != tt.event || e.User == nil || e.User.ID != tt.user.ID {
+					t.Errorf("unexpected event: %+v", e)
+				}
+			case <-ctx.Done():
+				t.Fatal("event not received")
+			}
+		})
+	}
+	// the cache is updated by the events before the handler is called, without users.info.
+	srv.SetError("users.info", "user_not_found")
+	d := bot.UserDirectory()
+	if u, ok := d.ByDisplayName("Ally"); !ok || u.ID != "U1" {
+		t.Errorf("want U1 by the new display name, got %+v, %v", u, ok)
+	}
+	if u, ok := d.ByEmail("bob@example.com"); !ok || u.ID != "U2" {
+		t.Errorf("want the joined user, got %+v, %v", u, ok)
+	}
+	if u, err := bot.GetUser(ctx, "U2"); err != nil || u.Name != "bob" {
+		t.Errorf("want bob from the cache, got %+v, %v", u, err)
+	}
+}
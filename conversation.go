@@ -0,0 +1,97 @@
+package slackbot
+
+import (
+	"context"
+
+	"github.com/ikawaha/slackbot/webapi"
+)
+
+type (
+	// Channel is an alias type of the web api conversation.
+	Channel = webapi.Channel
+
+	// ConversationType is an alias type of the web api conversation type.
+	ConversationType = webapi.ConversationType
+)
+
+// Conversation types.
+const (
+	PublicChannel  = webapi.PublicChannel
+	PrivateChannel = webapi.PrivateChannel
+	MPIM           = webapi.MPIM
+	IM             = webapi.IM
+)
+
+// ConversationsList lists all the conversations of the types in a Slack team.
+// If no type is given, it lists the public channels.
+// see. https://api.slack.com/methods/conversations.list
+func (c Client) ConversationsList(ctx context.Context, excludeArchived bool, types ...ConversationType) ([]Channel, error) {
+	return c.webAPIClient.ConversationsList(ctx, excludeArchived, types...)
+}
+
+// ConversationsInfo gets the information about the conversation.
+// The channel is the ID, or the name which starts with "#".
+// see. https://api.slack.com/methods/conversations.info
+func (c Client) ConversationsInfo(ctx context.Context, channel string) (*Channel, error) {
+	return c.webAPIClient.ConversationsInfo(ctx, channel)
+}
+
+// ConversationsMembers lists the user IDs of the members of the conversation.
+// The channel is the ID, or the name which starts with "#".
+// see. https://api.slack.com/methods/conversations.members
+func (c Client) ConversationsMembers(ctx context.Context, channel string) ([]string, error) {
+	return c.webAPIClient.ConversationsMembers(ctx, channel)
+}
+
+// ConversationsJoin joins the bot to the existing public channel.
+// The channel is the ID, or the name which starts with "#".
+// see. https://api.slack.com/methods/conversations.join
+func (c Client) ConversationsJoin(ctx context.Context, channel string) (*Channel, error) {
+	return c.webAPIClient.ConversationsJoin(ctx, channel)
+}
+
+// ConversationsOpen opens the direct message with the user, or the multi-person direct message with the users.
+// see. https://api.slack.com/methods/conversations.open
+func (c Client) ConversationsOpen(ctx context.Context, userIDs ...string) (*Channel, error) {
+	return c.webAPIClient.ConversationsOpen(ctx, userIDs...)
+}
+
+// ConversationsCreate creates the public or private channel.
+// see. https://api.slack.com/methods/conversations.create
+func (c Client) ConversationsCreate(ctx context.Context, name string, private bool) (*Channel, error) {
+	return c.webAPIClient.ConversationsCreate(ctx, name, private)
+}
+
+// ConversationsInvite invites the users to the channel.
+// The channel is the ID, or the name which starts with "#".
+// see. https://api.slack.com/methods/conversations.invite
+func (c Client) ConversationsInvite(ctx context.Context, channel string, userIDs ...string) (*Channel, error) {
+	return c.webAPIClient.ConversationsInvite(ctx, channel, userIDs...)
+}
+
+// ConversationsArchive archives the conversation.
+// The channel is the ID, or the name which starts with "#".
+// see. https://api.slack.com/methods/conversations.archive
+func (c Client) ConversationsArchive(ctx context.Context, channel string) error {
+	return c.webAPIClient.ConversationsArchive(ctx, channel)
+}
+
+// OpenDM opens the direct message with the user and returns its channel ID.
+// required scopes: `im:write`
+func (c Client) OpenDM(ctx context.Context, userID string) (string, error) {
+	ch, err := c.webAPIClient.ConversationsOpen(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	return ch.ID, nil
+}
+
+// PostDirectMessage sends a direct message to the user.
+// required scopes: `im:write`, `chat:write`
+func (c Client) PostDirectMessage(ctx context.Context, userID, msg string, opts ...MessageOption) error {
+	channelID, err := c.OpenDM(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return c.PostMessage(ctx, channelID, msg, opts...)
+}
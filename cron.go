@@ -0,0 +1,196 @@
+package slackbot
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSpec is the parsed cron expression, "minute hour day-of-month month day-of-week".
+// Each field is the bit set of the matching values.
+type cronSpec struct {
+	minute, hour, dom, month, dow uint64
+	// anyDay is true if either of the day-of-month or the day-of-week is "*".
+	// Otherwise a day matches if it matches either of them, as cron does.
+	anyDay bool
+}
+
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField = cronField{name: "minute", min: 0, max: 59}
+	hourField   = cronField{name: "hour", min: 0, max: 23}
+	domField    = cronField{name: "day of month", min: 1, max: 31}
+	monthField  = cronField{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// 7 is also Sunday.
+	dowField = cronField{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronSearchLimit is how far the next time is searched, to give up the specs which never match, e.g. "0 0 30 2 *".
+const cronSearchLimit = 5 // years
+
+// parseCron parses the cron expression of 5 fields, or the descriptor such as "@daily".
+// The fields accept "*", the values, the names of months and days of week, the ranges "a-b",
+// the steps "*/n" and "a-b/n", and the lists of them separated by commas.
+func parseCron(spec string) (*cronSpec, error) {
+	expr := strings.TrimSpace(spec)
+	if v, ok := cronDescriptors[strings.ToLower(expr)]; ok {
+		expr = v
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("schedule spec error: %q, expected 5 fields, got %d", spec, len(fields))
+	}
+	var (
+		ret cronSpec
+		err error
+	)
+	for i, v := range []struct {
+		bits  *uint64
+		field cronField
+	}{
+		{bits: &ret.minute, field: minuteField},
+		{bits: &ret.hour, field: hourField},
+		{bits: &ret.dom, field: domField},
+		{bits: &ret.month, field: monthField},
+		{bits: &ret.dow, field: dowField},
+	} {
+		if *v.bits, err = v.field.parse(fields[i]); err != nil {
+			return nil, fmt.Errorf("schedule spec error: %q, %w", spec, err)
+		}
+	}
+	if ret.dow&(1<<7) != 0 {
+		ret.dow |= 1 << 0
+	}
+	ret.anyDay = fields[2] == "*" || fields[4] == "*"
+	return &ret, nil
+}
+
+func (f cronField) parse(s string) (uint64, error) {
+	var ret uint64
+	for _, v := range strings.Split(s, ",") {
+		bits, err := f.parseRange(v)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s: %q, %w", f.name, s, err)
+		}
+		ret |= bits
+	}
+	return ret, nil
+}
+
+func (f cronField) parseRange(s string) (uint64, error) {
+	r, step := s, 1
+	if i := strings.Index(s, "/"); i >= 0 {
+		n, err := strconv.Atoi(s[i+1:])
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid step: %q", s[i+1:])
+		}
+		r, step = s[:i], n
+	}
+	var lo, hi int
+	switch i := strings.Index(r, "-"); {
+	case r == "*":
+		lo, hi = f.min, f.max
+	case i >= 0:
+		var err error
+		if lo, err = f.value(r[:i]); err != nil {
+			return 0, err
+		}
+		if hi, err = f.value(r[i+1:]); err != nil {
+			return 0, err
+		}
+		if lo > hi {
+			return 0, fmt.Errorf("invalid range: %q", r)
+		}
+	default:
+		v, err := f.value(r)
+		if err != nil {
+			return 0, err
+		}
+		lo, hi = v, v
+		if step > 1 {
+			// "a/n" means from a to the max.
+			hi = f.max
+		}
+	}
+	var ret uint64
+	for v := lo; v <= hi; v += step {
+		ret |= 1 << uint(v)
+	}
+	return ret, nil
+}
+
+func (f cronField) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value: %q", s)
+	}
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("out of range: %d, not in [%d, %d]", v, f.min, f.max)
+	}
+	return v, nil
+}
+
+var errNeverMatch = errors.New("schedule never matches")
+
+// next returns the first time after t which matches the spec, in the location of t.
+// The wall clock times skipped by the daylight saving time transition do not match,
+// and the times repeated by the transition match only once.
+func (s *cronSpec) next(t time.Time) (time.Time, error) {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(cronSearchLimit, 0, 0)
+	for t.Before(limit) {
+		prev := t
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.matchDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, loc)
+		default:
+			return t, nil
+		}
+		// time.Date normalizes the time in the repeated hour to the earlier one, which must not go back.
+		if !t.After(prev) {
+			t = prev.Add(time.Minute)
+		}
+	}
+	return time.Time{}, errNeverMatch
+}
+
+func (s *cronSpec) matchDay(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.anyDay {
+		return dom && dow
+	}
+	return dom || dow
+}
@@ -0,0 +1,266 @@
+package slackbot
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	bits := func(vs ...int) uint64 {
+		var ret uint64
+		for _, v := range vs {
+			ret |= 1 << uint(v)
+		}
+		return ret
+	}
+	span := func(lo, hi, step int) uint64 {
+		var ret uint64
+		for v := lo; v <= hi; v += step {
+			ret |= 1 << uint(v)
+		}
+		return ret
+	}
+	testdata := []struct {
+		name string
+		spec string
+		want cronSpec
+	}{
+		{
+			name: "any",
+			spec: "* * * * *",
+			want: cronSpec{minute: span(0, 59, 1), hour: span(0, 23, 1), dom: span(1, 31, 1), month: span(1, 12, 1), dow: span(0, 7, 1), anyDay: true},
+		},
+		{
+			name: "values and names",
+			spec: "30 9 * jan,Jul mon-fri",
+			want: cronSpec{minute: bits(30), hour: bits(9), dom: span(1, 31, 1), month: bits(1, 7), dow: span(1, 5, 1), anyDay: true},
+		},
+		{
+			name: "steps",
+			spec: "*/15 9-17/4 1/10 * *",
+			want: cronSpec{minute: bits(0, 15, 30, 45), hour: bits(9, 13, 17), dom: bits(1, 11, 21, 31), month: span(1, 12, 1), dow: span(0, 7, 1), anyDay: true},
+		},
+		{
+			name: "lists of ranges",
+			spec: "0,5-7 0 1-3,15 * 1",
+			want: cronSpec{minute: bits(0, 5, 6, 7), hour: bits(0), dom: bits(1, 2, 3, 15), month: span(1, 12, 1), dow: bits(1)},
+		},
+		{
+			name: "sunday as 7",
+			spec: "0 0 * * 7",
+			want: cronSpec{minute: bits(0), hour: bits(0), dom: span(1, 31, 1), month: span(1, 12, 1), dow: bits(0, 7), anyDay: true},
+		},
+		{
+			name: "descriptor",
+			spec: " @Daily ",
+			want: cronSpec{minute: bits(0), hour: bits(0), dom: span(1, 31, 1), month: span(1, 12, 1), dow: span(0, 7, 1), anyDay: true},
+		},
+		{
+			name: "weekly descriptor",
+			spec: "@weekly",
+			want: cronSpec{minute: bits(0), hour: bits(0), dom: span(1, 31, 1), month: span(1, 12, 1), dow: bits(0), anyDay: true},
+		},
+		{
+			name: "yearly descriptor",
+			spec: "@yearly",
+			want: cronSpec{minute: bits(0), hour: bits(0), dom: bits(1), month: bits(1), dow: span(0, 7, 1), anyDay: true},
+		},
+	}
+	for _, tt := range testdata {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseCron(tt.spec)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if *got != tt.want {
+				t.Errorf("got %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCron_Error(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"@every",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"a * * * *",
+		"* * * foo *",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"*/x * * * *",
+		"1,,2 * * * *",
+	} {
+		if _, err := parseCron(spec); err == nil {
+			t.Errorf("%q: want error", spec)
+		}
+	}
+}
+
+func TestCronSpec_Next(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone database is not available: %v", err)
+	}
+	testdata := []struct {
+		name string
+		spec string
+		from string
+		want string
+	}{
+		{name: "weekday", spec: "30 9 * * mon-fri", from: "2026-10-16T10:00:00-04:00", want: "2026-10-19T09:30:00-04:00"},
+		{name: "step", spec: "*/15 * * * *", from: "2026-10-16T10:07:12-04:00", want: "2026-10-16T10:15:00-04:00"},
+		{name: "strictly after", spec: "*/15 * * * *", from: "2026-10-16T10:15:00-04:00", want: "2026-10-16T10:30:00-04:00"},
+		{name: "next year", spec: "0 0 1 * *", from: "2026-12-15T00:00:00-05:00", want: "2027-01-01T00:00:00-05:00"},
+		{name: "leap day", spec: "0 9 29 feb *", from: "2026-03-01T00:00:00-05:00", want: "2028-02-29T09:00:00-05:00"},
+		{name: "sunday as 7", spec: "0 0 * * 7", from: "2026-10-16T10:00:00-04:00", want: "2026-10-18T00:00:00-04:00"},
+		{name: "descriptor", spec: "@weekly", from: "2026-10-16T10:00:00-04:00", want: "2026-10-18T00:00:00-04:00"},
+		// either of the day of month and the day of week matches, when neither is "*".
+		{name: "day of week or day of month", spec: "0 0 13 * fri", from: "2026-10-14T00:00:00-04:00", want: "2026-10-16T00:00:00-04:00"},
+		{name: "day of month or day of week", spec: "0 0 13 * fri", from: "2026-11-07T00:00:00-05:00", want: "2026-11-13T00:00:00-05:00"},
+		{name: "day of month with any day of week", spec: "0 0 13 * *", from: "2026-10-14T00:00:00-04:00", want: "2026-11-13T00:00:00-05:00"},
+		{name: "day of week with any day of month", spec: "0 0 * * fri", from: "2026-10-14T00:00:00-04:00", want: "2026-10-16T00:00:00-04:00"},
+		// 2:30 does not exist on the day of the spring forward, 2026-03-08.
+		{name: "spring forward", spec: "30 2 * * *", from: "2026-03-07T03:00:00-05:00", want: "2026-03-09T02:30:00-04:00"},
+		{name: "spring forward hourly", spec: "0 * * * *", from: "2026-03-08T01:30:00-05:00", want: "2026-03-08T03:00:00-04:00"},
+		// 1:30 is repeated on the day of the fall back, 2026-11-01, and matches only once.
+		{name: "fall back", spec: "30 1 * * *", from: "2026-10-31T12:00:00-04:00", want: "2026-11-01T01:30:00-04:00"},
+		{name: "fall back repeated", spec: "30 1 * * *", from: "2026-11-01T01:30:00-04:00", want: "2026-11-02T01:30:00-05:00"},
+		{name: "fall back hourly", spec: "0 * * * *", from: "2026-11-01T01:30:00-04:00", want: "2026-11-01T02:00:00-05:00"},
+	}
+	for _, tt := range testdata {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := parseCron(tt.spec)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			from, err := time.Parse(time.RFC3339, tt.from)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got, err := s.next(from.In(ny))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Location() != ny {
+				t.Errorf("want the location %v, got %v", ny, got.Location())
+			}
+			if v := got.Format(time.RFC3339); v != tt.want {
+				t.Errorf("got %s, want %s", v, tt.want)
+			}
+		})
+	}
+}
+
+func TestCronSpec_Next_NeverMatch(t *testing.T) {
+	for _, spec := range []string{"0 0 30 2 *", "0 0 31 apr,jun,sep,nov *"} {
+		s, err := parseCron(spec)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := s.next(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)); !errors.Is(err, errNeverMatch) {
+			t.Errorf("%q: want errNeverMatch, got %v", spec, err)
+		}
+	}
+}
+
+func TestScheduler(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	s := newScheduler(func(userID string) (*time.Location, bool) {
+		return tokyo, userID == "U1"
+	})
+	var ran []JobID
+	newJob := func(spec string, userID string) *job {
+		c, err := parseCron(spec)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		j := &job{spec: spec, cron: c, loc: time.UTC, userID: userID}
+		j.fn = func(context.Context) error {
+			ran = append(ran, j.id)
+			return nil
+		}
+		return j
+	}
+	if _, err := s.add(newJob("0 0 30 2 *", "")); !errors.Is(err, errNeverMatch) {
+		t.Errorf("want errNeverMatch, got %v", err)
+	}
+
+	daily, err := s.add(newJob("0 9 * * *", "U1"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	next, ok := s.nextRun(daily)
+	if !ok || next.Location() != tokyo || next.Hour() != 9 || next.Minute() != 0 {
+		t.Errorf("want 9:00 in the user's time zone, got %v, %v", next, ok)
+	}
+	unknown, err := s.add(newJob("0 9 * * *", "UX"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if next, _ := s.nextRun(unknown); next.Location() != time.UTC {
+		t.Errorf("want the default time zone for the unknown user, got %v", next.Location())
+	}
+	if !s.remove(unknown) || s.remove(unknown) {
+		t.Error("want the job removed once")
+	}
+
+	// the job is due at its next run, and advances to the day after.
+	due, wait := s.due(next)
+	if len(due) != 1 || due[0].id != daily {
+		t.Fatalf("want the job due, got %+v", due)
+	}
+	s.exec(context.Background(), due[0])
+	if len(ran) != 1 || ran[0] != daily {
+		t.Errorf("want the job run, got %v", ran)
+	}
+	if got, _ := s.nextRun(daily); !got.Equal(next.AddDate(0, 0, 1)) || wait != 24*time.Hour {
+		t.Errorf("want the next run on the next day, got %v, wait %v", got, wait)
+	}
+	if due, _ := s.due(next.Add(time.Hour)); len(due) != 0 {
+		t.Errorf("want no jobs due, got %+v", due)
+	}
+
+	s.remove(daily)
+	if _, wait := s.due(next); wait >= 0 {
+		t.Errorf("want no wait without jobs, got %v", wait)
+	}
+}
+
+func TestScheduler_Run(t *testing.T) {
+	s := newScheduler(nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- s.run(ctx) }()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		s.mux.Lock()
+		running := s.running
+		s.mux.Unlock()
+		if running {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("scheduler did not start")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if err := s.run(ctx); !errors.Is(err, ErrSchedulerRunning) {
+		t.Errorf("want ErrSchedulerRunning, got %v", err)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := s.run(ctx); err != nil {
+		t.Errorf("want the canceled scheduler to stop, got %v", err)
+	}
+}
@@ -18,4 +18,77 @@ const (
 
 	// SlashCommand is a slash command.
 	SlashCommand = socketmode.SlashCommand
+
+	// UserChange is a Slack event type.
+	// A member's data has changed. The client updates its user cache by the event.
+	UserChange = socketmode.UserChange
+
+	// TeamJoin is a Slack event type.
+	// A new member has joined. The client updates its user cache by the event.
+	TeamJoin = socketmode.TeamJoin
+
+	// ChannelCreated is a Slack event type.
+	// A channel was created. The client updates its channel cache by the event.
+	ChannelCreated = socketmode.ChannelCreated
+
+	// ChannelRename is a Slack event type.
+	// A channel was renamed. The client updates its channel cache by the event.
+	ChannelRename = socketmode.ChannelRename
+
+	// ChannelArchive is a Slack event type.
+	// A channel was archived. The client updates its channel cache by the event.
+	ChannelArchive = socketmode.ChannelArchive
+
+	// ChannelUnarchive is a Slack event type.
+	// A channel was unarchived. The client updates its channel cache by the event.
+	ChannelUnarchive = socketmode.ChannelUnarchive
+
+	// ChannelDeleted is a Slack event type.
+	// A channel was deleted. The client updates its channel cache by the event.
+	ChannelDeleted = socketmode.ChannelDeleted
 )
+
+const (
+	// BlockActions is an interactive event type.
+	// A user clicked a button or chose an item of an interactive component.
+	BlockActions = socketmode.BlockActions
+
+	// ViewSubmission is an interactive event type.
+	// A user submitted a modal view.
+	ViewSubmission = socketmode.ViewSubmission
+
+	// ViewClosed is an interactive event type.
+	// A user closed a modal view which notifies on close.
+	ViewClosed = socketmode.ViewClosed
+
+	// Shortcut is an interactive event type.
+	// A user called a global shortcut.
+	Shortcut = socketmode.Shortcut
+
+	// MessageAction is an interactive event type.
+	// A user called a message shortcut.
+	MessageAction = socketmode.MessageAction
+)
+
+// InteractionPayload is the payload of the interactive event.
+type InteractionPayload = socketmode.InteractionPayload
+
+// ViewResponse is the response payload to the view_submission event.
+type ViewResponse = socketmode.ViewResponse
+
+// DisconnectReason is the reason of the disconnect envelope.
+type DisconnectReason = socketmode.DisconnectReason
+
+const (
+	// DisconnectWarning is sent a few seconds before the connection is refreshed.
+	DisconnectWarning = socketmode.DisconnectWarning
+
+	// DisconnectRefreshRequested is sent when the connection is about to be refreshed.
+	DisconnectRefreshRequested = socketmode.DisconnectRefreshRequested
+
+	// DisconnectLinkDisabled is sent when the socket mode is turned off for the app.
+	DisconnectLinkDisabled = socketmode.DisconnectLinkDisabled
+)
+
+// ErrLinkDisabled matches the error returned when the socket mode is turned off for the app.
+var ErrLinkDisabled = socketmode.ErrLinkDisabled
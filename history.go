@@ -0,0 +1,95 @@
+package slackbot
+
+import (
+	"context"
+	"time"
+
+	"github.com/ikawaha/slackbot/webapi"
+)
+
+type (
+	// File is an alias type of the web api file.
+	File = webapi.File
+
+	// Reaction is an alias type of the web api reaction.
+	Reaction = webapi.Reaction
+
+	// HistoryOption is an alias type of the web api history option.
+	HistoryOption = webapi.HistoryOption
+
+	// MessageIterator is an alias type of the web api message iterator.
+	MessageIterator = webapi.MessageIterator
+)
+
+// Oldest retrieves only the messages after the timestamp.
+func Oldest(ts string) HistoryOption {
+	return webapi.Oldest(ts)
+}
+
+// Latest retrieves only the messages before the timestamp.
+func Latest(ts string) HistoryOption {
+	return webapi.Latest(ts)
+}
+
+// Since retrieves only the messages after the time.
+func Since(t time.Time) HistoryOption {
+	return webapi.Since(t)
+}
+
+// Until retrieves only the messages before the time.
+func Until(t time.Time) HistoryOption {
+	return webapi.Until(t)
+}
+
+// Inclusive includes the messages of the oldest and the latest timestamps.
+func Inclusive() HistoryOption {
+	return webapi.Inclusive()
+}
+
+// HistoryLimit sets the number of messages to retrieve in a page.
+func HistoryLimit(n int) HistoryOption {
+	return webapi.HistoryLimit(n)
+}
+
+// IncludeAllMetadata retrieves the metadata of the messages.
+func IncludeAllMetadata() HistoryOption {
+	return webapi.IncludeAllMetadata()
+}
+
+// Timestamp returns the message timestamp of the time.
+func Timestamp(t time.Time) string {
+	return webapi.Timestamp(t)
+}
+
+// ParseTimestamp returns the time of the message timestamp.
+func ParseTimestamp(ts string) (time.Time, error) {
+	return webapi.ParseTimestamp(ts)
+}
+
+// ConversationsHistory retrieves all the messages of the conversation, from the newest to the oldest.
+// see. https://api.slack.com/methods/conversations.history
+func (c Client) ConversationsHistory(ctx context.Context, channel string, opts ...HistoryOption) ([]webapi.Message, error) {
+	return c.webAPIClient.ConversationsHistory(ctx, channel, opts...)
+}
+
+// ConversationsReplies retrieves all the messages of the thread, from the parent message to the latest reply.
+// see. https://api.slack.com/methods/conversations.replies
+func (c Client) ConversationsReplies(ctx context.Context, channel, threadTS string, opts ...HistoryOption) ([]webapi.Message, error) {
+	return c.webAPIClient.ConversationsReplies(ctx, channel, threadTS, opts...)
+}
+
+// NewHistoryIterator creates the iterator over the messages of the conversation, from the newest to the oldest.
+func (c Client) NewHistoryIterator(channel string, opts ...HistoryOption) *MessageIterator {
+	return c.webAPIClient.NewHistoryIterator(channel, opts...)
+}
+
+// NewRepliesIterator creates the iterator over the messages of the thread, from the parent message to the latest reply.
+func (c Client) NewRepliesIterator(channel, threadTS string, opts ...HistoryOption) *MessageIterator {
+	return c.webAPIClient.NewRepliesIterator(channel, threadTS, opts...)
+}
+
+// ThreadOf retrieves all the messages of the thread of the event.
+// If the event is not in a thread, it returns the message of the event and its replies if any.
+func (c Client) ThreadOf(ctx context.Context, e *Event, opts ...HistoryOption) ([]webapi.Message, error) {
+	return c.webAPIClient.ConversationsReplies(ctx, e.Channel, e.ReplyThreadTS(), opts...)
+}
@@ -0,0 +1,26 @@
+package slackapi
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Backoff returns the time to wait before the attempt following the n-th failed attempt.
+// The base is doubled for each subsequent attempt and capped by the max, if it is positive.
+// The jitter is the fraction of the delay to be randomized, in the range [0, 1].
+func Backoff(n int, base, max time.Duration, jitter float64) time.Duration {
+	d := base
+	for i := 1; i < n && (max <= 0 || d < max); i++ {
+		d *= 2
+	}
+	if max > 0 && d > max {
+		d = max
+	}
+	if jitter > 0 && d > 0 {
+		if jitter > 1 {
+			jitter = 1
+		}
+		d -= time.Duration(rand.Float64() * jitter * float64(d))
+	}
+	return d
+}
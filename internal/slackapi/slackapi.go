@@ -0,0 +1,134 @@
+// Package slackapi implements the parts of the Slack Web API protocol shared by the webapi and socketmode clients.
+package slackapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Error codes the client sets on the APIError when the response has no error code.
+const (
+	CodeRateLimited  = "ratelimited"
+	CodeUnknownError = "unknown_error"
+)
+
+// ResponseMetadata is the metadata of the Web API response.
+type ResponseMetadata struct {
+	Messages   []string `json:"messages,omitempty"`
+	Warnings   []string `json:"warnings,omitempty"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+}
+
+// APIError represents the error of the Slack Web API call.
+type APIError struct {
+	// Method is the name of the Web API method, e.g. "chat.postMessage".
+	Method string
+	// Code is the error code of the response, e.g. "channel_not_found".
+	Code string
+	// Needed is the scope needed for the method on the missing_scope error.
+	Needed string
+	// Provided is the scopes of the token on the missing_scope error.
+	Provided string
+	// Warnings is the warnings of the response.
+	Warnings []string
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// ResponseMetadata is the metadata of the response, which may have the detailed messages.
+	ResponseMetadata ResponseMetadata
+}
+
+// Error implements the error interface.
+func (e *APIError) Error() string {
+	var b strings.Builder
+	b.WriteString("slack ")
+	b.WriteString(e.Method)
+	b.WriteString(" failed: ")
+	switch {
+	case e.Code != "":
+		b.WriteString(e.Code)
+	default:
+		fmt.Fprintf(&b, "%d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	if e.Needed != "" || e.Provided != "" {
+		fmt.Fprintf(&b, ": needed: %q, provided: %q", e.Needed, e.Provided)
+	}
+	if len(e.ResponseMetadata.Messages) > 0 {
+		fmt.Fprintf(&b, ", %q", e.ResponseMetadata.Messages)
+	}
+	return b.String()
+}
+
+// Is returns true if the target is an APIError of the same code.
+// If the target has the method or the status code, they are also compared.
+func (e *APIError) Is(target error) bool {
+	t, ok := target.(*APIError)
+	if !ok {
+		return false
+	}
+	if t.Method != "" && t.Method != e.Method {
+		return false
+	}
+	if t.StatusCode != 0 && t.StatusCode != e.StatusCode {
+		return false
+	}
+	return t.Code == "" || t.Code == e.Code
+}
+
+// Response is the common part of the Web API responses.
+type Response struct {
+	OK               bool             `json:"ok"`
+	Error            string           `json:"error"`
+	Needed           string           `json:"needed"`
+	Provided         string           `json:"provided"`
+	Warning          string           `json:"warning"`
+	ResponseMetadata ResponseMetadata `json:"response_metadata"`
+}
+
+// DecodeResponse decodes the common part of the response, or returns the APIError if the response is not ok.
+func DecodeResponse(method string, statusCode int, body []byte) (*Response, error) {
+	var r Response
+	decodeErr := json.Unmarshal(body, &r)
+	if statusCode == http.StatusOK && decodeErr == nil && r.OK {
+		return &r, nil
+	}
+	ret := &APIError{
+		Method:           method,
+		Code:             r.Error,
+		Needed:           r.Needed,
+		Provided:         r.Provided,
+		StatusCode:       statusCode,
+		ResponseMetadata: r.ResponseMetadata,
+	}
+	if r.Warning != "" {
+		ret.Warnings = strings.Split(r.Warning, ",")
+	}
+	if ret.Code == "" && statusCode == http.StatusTooManyRequests {
+		ret.Code = CodeRateLimited
+	}
+	if statusCode == http.StatusOK && decodeErr != nil {
+		return nil, fmt.Errorf("response body unmarshal error: body=%q, %w", string(body), decodeErr)
+	}
+	if ret.Code == "" && statusCode == http.StatusOK {
+		ret.Code = CodeUnknownError
+	}
+	return nil, ret
+}
+
+// NormalizeBaseURL validates the base URL of the Web API, e.g. "https://slack.com/api",
+// and returns it with the trailing slash, to which the method names are appended.
+func NormalizeBaseURL(u string) (string, error) {
+	v, err := url.Parse(u)
+	if err != nil {
+		return "", fmt.Errorf("invalid base url: %w", err)
+	}
+	if v.Scheme != "http" && v.Scheme != "https" {
+		return "", fmt.Errorf("invalid base url scheme: %q", u)
+	}
+	if !strings.HasSuffix(u, "/") {
+		u += "/"
+	}
+	return u, nil
+}
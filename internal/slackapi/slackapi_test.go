@@ -0,0 +1,162 @@
+package slackapi
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDecodeResponse(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		want       *APIError
+		wantErr    bool
+	}{
+		{name: "ok", statusCode: http.StatusOK, body: `{"ok":true}`},
+		{
+			name:       "error code",
+			statusCode: http.StatusOK,
+			body:       `{"ok":false,"error":"channel_not_found"}`,
+			want:       &APIError{Method: "m", Code: "channel_not_found", StatusCode: http.StatusOK},
+		},
+		{
+			name:       "missing scope",
+			statusCode: http.StatusOK,
+			body:       `{"ok":false,"error":"missing_scope","needed":"chat:write","provided":"users:read","warning":"a,b","response_metadata":{"messages":["x"]}}`,
+			want: &APIError{
+				Method:           "m",
+				Code:             "missing_scope",
+				Needed:           "chat:write",
+				Provided:         "users:read",
+				Warnings:         []string{"a", "b"},
+				StatusCode:       http.StatusOK,
+				ResponseMetadata: ResponseMetadata{Messages: []string{"x"}},
+			},
+		},
+		{
+			name:       "not ok without error code",
+			statusCode: http.StatusOK,
+			body:       `{"ok":false}`,
+			want:       &APIError{Method: "m", Code: CodeUnknownError, StatusCode: http.StatusOK},
+		},
+		{
+			name:       "rate limited without body",
+			statusCode: http.StatusTooManyRequests,
+			body:       ``,
+			want:       &APIError{Method: "m", Code: CodeRateLimited, StatusCode: http.StatusTooManyRequests},
+		},
+		{
+			name:       "server error",
+			statusCode: http.StatusBadGateway,
+			body:       `<html>bad gateway</html>`,
+			want:       &APIError{Method: "m", StatusCode: http.StatusBadGateway},
+		},
+		{name: "undecodable", statusCode: http.StatusOK, body: `<html>`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := DecodeResponse("m", tt.statusCode, []byte(tt.body))
+			switch {
+			case tt.wantErr:
+				var apiErr *APIError
+				if err == nil || errors.As(err, &apiErr) {
+					t.Errorf("want a decode error, got %v", err)
+				}
+			case tt.want == nil:
+				if err != nil || r == nil || !r.OK {
+					t.Errorf("want ok, got %+v, %v", r, err)
+				}
+			default:
+				var got *APIError
+				if !errors.As(err, &got) {
+					t.Fatalf("want APIError, got %v", err)
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("want %+v, got %+v", tt.want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	err := error(&APIError{Method: "chat.postMessage", Code: "channel_not_found", StatusCode: http.StatusOK})
+	tests := []struct {
+		target *APIError
+		want   bool
+	}{
+		{target: &APIError{Code: "channel_not_found"}, want: true},
+		{target: &APIError{Code: "not_in_channel"}, want: false},
+		{target: &APIError{Method: "chat.postMessage", Code: "channel_not_found"}, want: true},
+		{target: &APIError{Method: "chat.update", Code: "channel_not_found"}, want: false},
+		{target: &APIError{StatusCode: http.StatusOK}, want: true},
+		{target: &APIError{StatusCode: http.StatusInternalServerError}, want: false},
+	}
+	for _, tt := range tests {
+		if got := errors.Is(err, tt.target); got != tt.want {
+			t.Errorf("errors.Is(%+v): want %v, got %v", tt.target, tt.want, got)
+		}
+	}
+	if got, want := err.Error(), "slack chat.postMessage failed: channel_not_found"; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+	if got, want := (&APIError{Method: "m", StatusCode: http.StatusBadGateway}).Error(), "slack m failed: 502 Bad Gateway"; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
+
+func TestNormalizeBaseURL(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "https://slack.com/api/", want: "https://slack.com/api/"},
+		{in: "https://slack.com/api", want: "https://slack.com/api/"},
+		{in: "http://127.0.0.1:8080/api", want: "http://127.0.0.1:8080/api/"},
+		{in: "ftp://slack.com/api", wantErr: true},
+		{in: "slack.com/api", wantErr: true},
+		{in: "http://[::1", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeBaseURL(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%q: unexpected error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%q: want %q, got %q", tt.in, tt.want, got)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		n    int
+		base time.Duration
+		max  time.Duration
+		want time.Duration
+	}{
+		{n: 1, base: time.Second, max: time.Minute, want: time.Second},
+		{n: 2, base: time.Second, max: time.Minute, want: 2 * time.Second},
+		{n: 4, base: time.Second, max: time.Minute, want: 8 * time.Second},
+		{n: 10, base: time.Second, max: time.Minute, want: time.Minute},
+		{n: 100, base: time.Second, max: time.Minute, want: time.Minute},
+		{n: 3, base: time.Second, want: 4 * time.Second},
+		{n: 3, want: 0},
+	}
+	for _, tt := range tests {
+		if got := Backoff(tt.n, tt.base, tt.max, 0); got != tt.want {
+			t.Errorf("Backoff(%d, %v, %v): want %v, got %v", tt.n, tt.base, tt.max, tt.want, got)
+		}
+	}
+	for i := 0; i < 100; i++ {
+		if got := Backoff(3, time.Second, time.Minute, 1); got < 0 || got > 4*time.Second {
+			t.Fatalf("want in [0, 4s], got %v", got)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package slackbot
+
+import (
+	"github.com/ikawaha/slackbot/blocks"
+	"github.com/ikawaha/slackbot/webapi"
+)
+
+type (
+	// MessageOption is an alias type of the web api message option.
+	MessageOption = webapi.MessageOption
+
+	// MessageResponse is an alias type of the web api message response.
+	MessageResponse = webapi.MessageResponse
+
+	// Attachment is an alias type of the web api message attachment.
+	Attachment = webapi.Attachment
+)
+
+// Blocks sets the Block Kit blocks of the message.
+// The text of the message is used as the fallback for notifications.
+func Blocks(bs ...blocks.Block) MessageOption {
+	return webapi.Blocks(bs...)
+}
+
+// Attachments sets the legacy secondary attachments of the message.
+func Attachments(as ...Attachment) MessageOption {
+	return webapi.Attachments(as...)
+}
+
+// ThreadTS posts the message as a reply to the thread of the parent message timestamp.
+func ThreadTS(ts string) MessageOption {
+	return webapi.ThreadTS(ts)
+}
+
+// ReplyBroadcast makes the thread reply visible to everyone in the channel.
+func ReplyBroadcast() MessageOption {
+	return webapi.ReplyBroadcast()
+}
+
+// UnfurlLinks enables or disables unfurling of primarily text-based content.
+func UnfurlLinks(enable bool) MessageOption {
+	return webapi.UnfurlLinks(enable)
+}
+
+// UnfurlMedia enables or disables unfurling of media content.
+func UnfurlMedia(enable bool) MessageOption {
+	return webapi.UnfurlMedia(enable)
+}
+
+// Mrkdwn enables or disables the Slack markup parsing of the message text.
+func Mrkdwn(enable bool) MessageOption {
+	return webapi.Mrkdwn(enable)
+}
+
+// Username sets the bot's user name of the message.
+// required scopes: `chat:write.customize`
+func Username(name string) MessageOption {
+	return webapi.Username(name)
+}
+
+// IconEmoji sets the emoji to use as the icon of the message.
+// required scopes: `chat:write.customize`
+func IconEmoji(emoji string) MessageOption {
+	return webapi.IconEmoji(emoji)
+}
+
+// IconURL sets the URL to an image to use as the icon of the message.
+// required scopes: `chat:write.customize`
+func IconURL(u string) MessageOption {
+	return webapi.IconURL(u)
+}
+
+// WithMetadata sets the metadata of the message.
+func WithMetadata(eventType string, payload map[string]interface{}) MessageOption {
+	return webapi.WithMetadata(eventType, payload)
+}
@@ -1,6 +1,9 @@
 package slackbot
 
 import (
+	"net/http"
+	"time"
+
 	"github.com/ikawaha/slackbot/socketmode"
 	"github.com/ikawaha/slackbot/webapi"
 )
@@ -45,6 +48,178 @@ func SetBotID(name string) Option {
 	}
 }
 
+// SetReconnectPolicy sets the policy of retrying reconnection to the socket mode server.
+func SetReconnectPolicy(p ReconnectPolicy) Option {
+	return func(c *config) error {
+		c.AddSocketModeOption(socketmode.SetReconnectPolicy(p))
+		return nil
+	}
+}
+
+// OnReconnect sets the hook called after each reconnect attempt to the socket mode server.
+func OnReconnect(h ReconnectHook) Option {
+	return func(c *config) error {
+		c.AddSocketModeOption(socketmode.OnReconnect(h))
+		return nil
+	}
+}
+
+// OnDisconnect sets the hook called when the client receives a disconnect envelope.
+func OnDisconnect(h DisconnectHook) Option {
+	return func(c *config) error {
+		c.AddSocketModeOption(socketmode.OnDisconnect(h))
+		return nil
+	}
+}
+
+// Connections sets the number of socket mode connections to keep open.
+// Slack allows up to 10 connections per app and spreads events across them,
+// so events are not dropped while one of the connections is refreshed.
+func Connections(n int) Option {
+	return func(c *config) error {
+		c.AddSocketModeOption(socketmode.Connections(n))
+		return nil
+	}
+}
+
+// AckDeadline sets the time to wait for a handler to acknowledge the event.
+// When the deadline expires, the event is acknowledged without a response payload.
+func AckDeadline(d time.Duration) Option {
+	return func(c *config) error {
+		c.AddSocketModeOption(socketmode.AckDeadline(d))
+		return nil
+	}
+}
+
+// SetBaseURL sets the base URL of the Web API for both the Web API client and the socket mode client,
+// e.g. the URL of a fake server for testing or of a gateway.
+func SetBaseURL(u string) Option {
+	return func(c *config) error {
+		c.AddWebAPIOption(webapi.BaseURL(u))
+		c.AddSocketModeOption(socketmode.BaseURL(u))
+		return nil
+	}
+}
+
+// SetHTTPClient sets the HTTP client to call the Web API.
+// The socket mode WebSocket connections are dialed directly, not by the HTTP client.
+func SetHTTPClient(hc *http.Client) Option {
+	return func(c *config) error {
+		c.AddWebAPIOption(webapi.HTTPClient(hc))
+		c.AddSocketModeOption(socketmode.HTTPClient(hc))
+		return nil
+	}
+}
+
+// SetTransport sets the round tripper of the HTTP client to call the Web API, e.g. to go through a proxy.
+// The socket mode WebSocket connections are dialed directly, so they do not go through the proxy.
+func SetTransport(rt http.RoundTripper) Option {
+	return func(c *config) error {
+		c.AddWebAPIOption(webapi.Transport(rt))
+		c.AddSocketModeOption(socketmode.Transport(rt))
+		return nil
+	}
+}
+
+// DisableThrottle disables the client-side throttling of the Web API calls by the rate limit tiers.
+// The rate-limited calls are still retried according to the Retry-After header.
+func DisableThrottle() Option {
+	return func(c *config) error {
+		c.AddWebAPIOption(webapi.DisableThrottle())
+		return nil
+	}
+}
+
+// MaxRateLimitRetries sets the maximum number of retries of the Web API call rate-limited with HTTP 429.
+func MaxRateLimitRetries(n int) Option {
+	return func(c *config) error {
+		c.AddWebAPIOption(webapi.MaxRateLimitRetries(n))
+		return nil
+	}
+}
+
+// SetRetryPolicy sets the policy of retrying the Web API calls which failed transiently.
+// By default, only the calls of idempotent methods are retried.
+func SetRetryPolicy(p RetryPolicy) Option {
+	return func(c *config) error {
+		c.AddWebAPIOption(webapi.SetRetryPolicy(p))
+		return nil
+	}
+}
+
+// DedupPostMessage enables the retries of posting messages on transient failures with a guard against double posts.
+// required scopes: `channels:history`, `groups:history`, `im:history` or `mpim:history` for the channel
+func DedupPostMessage() Option {
+	return func(c *config) error {
+		c.AddWebAPIOption(webapi.DedupPostMessage())
+		return nil
+	}
+}
+
+// CacheChannels lists all the public and private channels in a Slack team and caches them on startup.
+// Without it, the channels are cached on the first lookup by the name.
+func CacheChannels() Option {
+	return func(c *config) error {
+		c.AddWebAPIOption(webapi.CacheChannels())
+		return nil
+	}
+}
+
+// ChannelsCacheTTL sets the time to live of the channel cache.
+// The expired cache is refreshed in the background on the next lookup, not by a timer.
+func ChannelsCacheTTL(d time.Duration) Option {
+	return func(c *config) error {
+		c.AddWebAPIOption(webapi.ChannelsCacheTTL(d))
+		return nil
+	}
+}
+
+// SetCacheStore sets the store to persist the caches of the client, e.g. a FileStore to warm-start
+// from the cache saved by the previous run instead of listing all users on startup.
+// The caches are saved when they are refreshed and when the client is closed,
+// and the loaded caches are refreshed in the background after the warm start.
+// Note that the refresh lists all users and channels again, not the changes since the cache was saved:
+// the warm start makes the startup fast, but does not reduce the calls of the list methods.
+func SetCacheStore(s CacheStore) Option {
+	return func(c *config) error {
+		c.AddWebAPIOption(webapi.SetCacheStore(s))
+		return nil
+	}
+}
+
+// UsersCacheTTL sets the time to live of the user cache.
+// The expired cache is refreshed in the background on the next lookup, not by a timer.
+func UsersCacheTTL(d time.Duration) Option {
+	return func(c *config) error {
+		c.AddWebAPIOption(webapi.UsersCacheTTL(d))
+		return nil
+	}
+}
+
+// PageLimit sets the number of items to return in a page of the list methods, e.g. users.list.
+func PageLimit(n int) Option {
+	return func(c *config) error {
+		c.AddWebAPIOption(webapi.PageLimit(n))
+		return nil
+	}
+}
+
+// WebAPIOption passes the option to the Web API client as it is.
+func WebAPIOption(o webapi.Option) Option {
+	return func(c *config) error {
+		c.AddWebAPIOption(o)
+		return nil
+	}
+}
+
+// SocketModeOption passes the option to the socket mode client as it is.
+func SocketModeOption(o socketmode.Option) Option {
+	return func(c *config) error {
+		c.AddSocketModeOption(o)
+		return nil
+	}
+}
+
 // Debug is the debug option.
 func Debug() Option {
 	return func(c *config) error {
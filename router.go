@@ -0,0 +1,54 @@
+package slackbot
+
+import (
+	"context"
+	"sync"
+)
+
+// HandlerFunc handles a Slack event.
+type HandlerFunc func(ctx context.Context, e *Event) error
+
+// Router dispatches events to the handlers registered for each event type.
+type Router struct {
+	mux      sync.RWMutex
+	handlers map[EventType]HandlerFunc
+	fallback HandlerFunc
+}
+
+// NewRouter creates a router without handlers.
+func NewRouter() *Router {
+	return &Router{
+		handlers: map[EventType]HandlerFunc{},
+	}
+}
+
+// Handle registers the handler for the given event type.
+// If a handler already exists for the event type, Handle replaces it.
+func (r *Router) Handle(t EventType, h HandlerFunc) {
+	defer r.mux.Unlock()
+	r.mux.Lock()
+	r.handlers[t] = h
+}
+
+// HandleDefault registers the handler for the events that have no handler of their own.
+func (r *Router) HandleDefault(h HandlerFunc) {
+	defer r.mux.Unlock()
+	r.mux.Lock()
+	r.fallback = h
+}
+
+// HandleEvent passes the event to the handler registered for its type.
+// Events without a handler are passed to the default handler registered by HandleDefault,
+// and ignored if there is no default handler.
+func (r *Router) HandleEvent(ctx context.Context, e *Event) error {
+	r.mux.RLock()
+	h, ok := r.handlers[EventType(e.Type)]
+	if !ok {
+		h = r.fallback
+	}
+	r.mux.RUnlock()
+	if h == nil {
+		return nil
+	}
+	return h(ctx, e)
+}
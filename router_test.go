@@ -0,0 +1,71 @@
+package slackbot_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ikawaha/slackbot"
+)
+
+func TestRouter_HandleEvent(t *testing.T) {
+	errMessage := errors.New("message handler error")
+	tests := []struct {
+		name      string
+		eventType string
+		fallback  bool
+		want      string
+		wantErr   error
+	}{
+		{name: "routed", eventType: "message", want: "message", wantErr: errMessage},
+		{name: "routed with default", eventType: "app_mention", fallback: true, want: "app_mention"},
+		{name: "default", eventType: "reaction_added", fallback: true, want: "default"},
+		{name: "ignored", eventType: "reaction_added"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			r := slackbot.NewRouter()
+			r.Handle(slackbot.Message, func(_ context.Context, e *slackbot.Event) error {
+				got = "message"
+				return errMessage
+			})
+			r.Handle(slackbot.AppMention, func(_ context.Context, e *slackbot.Event) error {
+				got = "app_mention"
+				return nil
+			})
+			if tt.fallback {
+				r.HandleDefault(func(_ context.Context, e *slackbot.Event) error {
+					got = "default"
+					return nil
+				})
+			}
+			err := r.HandleEvent(context.Background(), &slackbot.Event{Type: tt.eventType})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("want error %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("want %q handler, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRouter_Handle_Replace(t *testing.T) {
+	var got int
+	r := slackbot.NewRouter()
+	r.Handle(slackbot.Message, func(context.Context, *slackbot.Event) error {
+		got = 1
+		return nil
+	})
+	r.Handle(slackbot.Message, func(context.Context, *slackbot.Event) error {
+		got = 2
+		return nil
+	})
+	if err := r.HandleEvent(context.Background(), &slackbot.Event{Type: "message"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != 2 {
+		t.Errorf("want the replaced handler, got %d", got)
+	}
+}
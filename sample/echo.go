@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
 	"strings"
 
 	"github.com/ikawaha/slackbot"
@@ -38,30 +39,28 @@ func main() {
 	fmt.Println("^C exits")
 
 	callPrefix := "<@" + bot.ID + ">"
-	for {
-		if err := bot.ReceiveMessage(context.TODO(), func(ctx context.Context, e *slackbot.Event) error {
-			switch slackbot.EventType(e.Type) {
-			case slackbot.Message:
-				u, ok := bot.User(e.UserID)
-				log.Printf("!!! user: %+v", u)
-				if !ok || u.IsBot {
-					return nil
-				}
-				if !strings.HasPrefix(e.Text, callPrefix) {
-					return nil
-				}
-				msg := "Hi, " + u.Name + ": " + strings.TrimPrefix(e.Text, callPrefix)
-				if err := bot.PostMessage(ctx, e.Channel, msg); err != nil {
-					return err
-				}
-			case slackbot.SlashCommand:
-				if err := bot.RespondToCommand(ctx, e.ResponseURL, e.Text, true); err != nil {
-					return err
-				}
-			}
+	r := slackbot.NewRouter()
+	r.Handle(slackbot.Message, func(ctx context.Context, e *slackbot.Event) error {
+		u, err := bot.GetUser(ctx, e.UserID)
+		if err != nil {
+			return err
+		}
+		if u.IsBot {
+			return nil
+		}
+		if !strings.HasPrefix(e.Text, callPrefix) {
 			return nil
-		}); err != nil {
-			log.Printf("%v", err)
 		}
+		msg := "Hi, " + u.Name + ": " + strings.TrimPrefix(e.Text, callPrefix)
+		return bot.PostMessage(ctx, e.Channel, msg)
+	})
+	r.Handle(slackbot.SlashCommand, func(ctx context.Context, e *slackbot.Event) error {
+		return bot.RespondToCommand(ctx, e.ResponseURL, e.Text, true)
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := bot.Run(ctx, r.HandleEvent); err != nil {
+		log.Fatal(err)
 	}
 }
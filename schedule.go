@@ -0,0 +1,303 @@
+package slackbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ikawaha/slackbot/webapi"
+)
+
+type (
+	// ScheduledMessage is an alias type of the web api scheduled message.
+	ScheduledMessage = webapi.ScheduledMessage
+
+	// ScheduleMessageResponse is an alias type of the web api response of the scheduled message.
+	ScheduleMessageResponse = webapi.ScheduleMessageResponse
+)
+
+// ScheduleMessage schedules the message to be sent to the channel at the time by Slack,
+// and returns the ID of the scheduled message. The time must be within 120 days.
+// The channel is the ID, or the name which starts with "#".
+// see. https://api.slack.com/methods/chat.scheduleMessage
+func (c Client) ScheduleMessage(ctx context.Context, channel string, postAt time.Time, msg string, opts ...MessageOption) (string, error) {
+	r, err := c.webAPIClient.ScheduleMessage(ctx, channel, postAt, msg, opts...)
+	if err != nil {
+		return "", err
+	}
+	return r.ScheduledMessageID, nil
+}
+
+// ScheduledMessages lists the messages scheduled by the bot and not sent yet.
+// The channel is the ID, or the name which starts with "#"; empty lists the messages of all channels.
+// see. https://api.slack.com/methods/chat.scheduledMessages.list
+func (c Client) ScheduledMessages(ctx context.Context, channel string, oldest, latest time.Time) ([]ScheduledMessage, error) {
+	return c.webAPIClient.ScheduledMessages(ctx, channel, oldest, latest)
+}
+
+// DeleteScheduledMessage deletes the message scheduled by the bot before it is sent.
+// see. https://api.slack.com/methods/chat.deleteScheduledMessage
+func (c Client) DeleteScheduledMessage(ctx context.Context, channel, id string) error {
+	return c.webAPIClient.DeleteScheduledMessage(ctx, channel, id)
+}
+
+// JobID identifies the job scheduled on the client.
+type JobID int
+
+// JobFunc is the function of the scheduled job.
+type JobFunc func(ctx context.Context) error
+
+// ScheduleOption represents the option of the scheduled job.
+type ScheduleOption func(*job) error
+
+// InLocation interprets the schedule in the time zone, e.g. time.LoadLocation("Asia/Tokyo").
+// The default is the local time zone of the process.
+func InLocation(loc *time.Location) ScheduleOption {
+	return func(j *job) error {
+		if loc == nil {
+			return errors.New("location is nil")
+		}
+		j.loc = loc
+		return nil
+	}
+}
+
+// InUserTimezone interprets the schedule in the time zone of the user, e.g. 9:00 in the morning of the user.
+// The time zone is looked up in the client's user cache each time the next run is computed,
+// so that the job follows the change of the user's time zone.
+func InUserTimezone(userID string) ScheduleOption {
+	return func(j *job) error {
+		if userID == "" {
+			return errors.New("user ID is empty")
+		}
+		j.userID = userID
+		return nil
+	}
+}
+
+// ErrSchedulerRunning is returned when the scheduler of the client is already running.
+var ErrSchedulerRunning = errors.New("scheduler is already running")
+
+// Schedule registers the job which runs at the times of the cron spec, and returns the ID of the job.
+// The spec is the standard cron expression, "minute hour day-of-month month day-of-week",
+// e.g. "30 9 * * mon-fri", or a descriptor: @yearly, @monthly, @weekly, @daily or @hourly.
+// The jobs run while Run or RunScheduler of the client is running. Errors returned by the job are logged.
+// The context is used to look up the user of InUserTimezone, not to run the job.
+// The spec which never matches, e.g. "0 0 30 2 *", is rejected.
+func (c Client) Schedule(ctx context.Context, spec string, fn JobFunc, opts ...ScheduleOption) (JobID, error) {
+	if fn == nil {
+		return 0, errors.New("job is nil")
+	}
+	s, err := parseCron(spec)
+	if err != nil {
+		return 0, err
+	}
+	j := &job{
+		spec: spec,
+		cron: s,
+		fn:   fn,
+		loc:  time.Local,
+	}
+	for _, opt := range opts {
+		if err := opt(j); err != nil {
+			return 0, err
+		}
+	}
+	if j.userID != "" {
+		if _, err := c.webAPIClient.GetUser(ctx, j.userID); err != nil {
+			return 0, fmt.Errorf("user of the time zone error: %s, %w", j.userID, err)
+		}
+	}
+	return c.scheduler.add(j)
+}
+
+// ScheduleRecurringMessage registers the job which posts the message to the channel at the times of the cron spec,
+// e.g. the daily standup reminder: ScheduleRecurringMessage(ctx, "0 10 * * mon-fri", "#dev", "standup time!").
+// The channel is the ID, or the name which starts with "#".
+func (c Client) ScheduleRecurringMessage(ctx context.Context, spec, channel, msg string, opts ...ScheduleOption) (JobID, error) {
+	return c.Schedule(ctx, spec, func(ctx context.Context) error {
+		return c.PostMessage(ctx, channel, msg)
+	}, opts...)
+}
+
+// Unschedule removes the scheduled job. It returns false if the job is not found.
+// The run of the job in progress is not canceled.
+func (c Client) Unschedule(id JobID) bool {
+	return c.scheduler.remove(id)
+}
+
+// NextRun returns the next time when the scheduled job runs.
+func (c Client) NextRun(id JobID) (time.Time, bool) {
+	return c.scheduler.nextRun(id)
+}
+
+// RunScheduler runs the scheduled jobs until the context is canceled.
+// Run of the client runs the scheduler too, so RunScheduler is for the clients which call ReceiveMessage,
+// or which only post scheduled messages.
+// RunScheduler returns nil when the context is canceled, or ErrSchedulerRunning if the scheduler is already running.
+func (c Client) RunScheduler(ctx context.Context) error {
+	return c.scheduler.run(ctx)
+}
+
+// job is the job registered to the scheduler.
+type job struct {
+	id     JobID
+	spec   string
+	cron   *cronSpec
+	fn     JobFunc
+	loc    *time.Location
+	userID string
+	next   time.Time
+}
+
+// scheduler runs the jobs at the times of their cron specs.
+type scheduler struct {
+	mux     sync.Mutex
+	jobs    map[JobID]*job
+	seq     JobID
+	running bool
+	wake    chan struct{}
+	// userLocation returns the time zone of the user.
+	userLocation func(userID string) (*time.Location, bool)
+}
+
+func newScheduler(userLocation func(userID string) (*time.Location, bool)) *scheduler {
+	return &scheduler{
+		jobs:         map[JobID]*job{},
+		wake:         make(chan struct{}, 1),
+		userLocation: userLocation,
+	}
+}
+
+func (s *scheduler) add(j *job) (JobID, error) {
+	defer s.mux.Unlock()
+	s.mux.Lock()
+	next, err := j.cron.next(time.Now().In(s.location(j)))
+	if err != nil {
+		return 0, fmt.Errorf("schedule spec error: %q, %w", j.spec, err)
+	}
+	s.seq++
+	j.id, j.next = s.seq, next
+	s.jobs[j.id] = j
+	s.notify()
+	return j.id, nil
+}
+
+func (s *scheduler) remove(id JobID) bool {
+	defer s.mux.Unlock()
+	s.mux.Lock()
+	if _, ok := s.jobs[id]; !ok {
+		return false
+	}
+	delete(s.jobs, id)
+	s.notify()
+	return true
+}
+
+func (s *scheduler) nextRun(id JobID) (time.Time, bool) {
+	defer s.mux.Unlock()
+	s.mux.Lock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return time.Time{}, false
+	}
+	return j.next, true
+}
+
+// location returns the time zone of the job. The user's time zone is kept if the user is no longer found.
+// The caller must hold the lock.
+func (s *scheduler) location(j *job) *time.Location {
+	if j.userID != "" && s.userLocation != nil {
+		if loc, ok := s.userLocation(j.userID); ok {
+			j.loc = loc
+		}
+	}
+	return j.loc
+}
+
+// notify wakes up the loop to recompute the time to wait. The caller must hold the lock.
+func (s *scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *scheduler) run(ctx context.Context) error {
+	s.mux.Lock()
+	if s.running {
+		s.mux.Unlock()
+		return ErrSchedulerRunning
+	}
+	s.running = true
+	s.mux.Unlock()
+	defer func() {
+		s.mux.Lock()
+		s.running = false
+		s.mux.Unlock()
+	}()
+	for {
+		due, wait := s.due(time.Now())
+		for _, j := range due {
+			go s.exec(ctx, j)
+		}
+		var (
+			timer   *time.Timer
+			timeout <-chan time.Time
+		)
+		if wait >= 0 {
+			timer = time.NewTimer(wait)
+			timeout = timer.C
+		}
+		select {
+		case <-ctx.Done():
+		case <-s.wake:
+		case <-timeout:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+// due returns the jobs to run at the time, after advancing them to their next runs,
+// and the duration until the earliest next run. The duration is negative if there are no jobs.
+func (s *scheduler) due(now time.Time) ([]*job, time.Duration) {
+	defer s.mux.Unlock()
+	s.mux.Lock()
+	var (
+		ret      []*job
+		earliest time.Time
+	)
+	for id, j := range s.jobs {
+		if !j.next.After(now) {
+			ret = append(ret, j)
+			next, err := j.cron.next(now.In(s.location(j)))
+			if err != nil {
+				log.Printf("scheduler: job: %d, %v, removed", id, err)
+				delete(s.jobs, id)
+				continue
+			}
+			j.next = next
+		}
+		if earliest.IsZero() || j.next.Before(earliest) {
+			earliest = j.next
+		}
+	}
+	if earliest.IsZero() {
+		return ret, -1
+	}
+	return ret, earliest.Sub(now)
+}
+
+func (s *scheduler) exec(ctx context.Context, j *job) {
+	if err := j.fn(ctx); err != nil {
+		log.Printf("scheduled job error: job: %d, spec: %q, %v", j.id, j.spec, err)
+	}
+}
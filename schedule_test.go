@@ -0,0 +1,116 @@
+package slackbot_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ikawaha/slackbot"
+	"github.com/ikawaha/slackbot/slacktest"
+	"github.com/ikawaha/slackbot/webapi"
+)
+
+func TestClient_Schedule(t *testing.T) {
+	ctx := context.Background()
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	srv.AddChannel(webapi.Channel{ID: "C1", Name: "general"})
+	srv.AddUser(webapi.User{ID: "U2", Name: "bob", TZ: "Etc/GMT-9", TZOffset: 9 * 60 * 60})
+	bot, err := slackbot.New("xapp-token", "xoxb-token", slackbot.SetBaseURL(srv.URL()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer bot.Close()
+
+	id, err := bot.ScheduleRecurringMessage(ctx, "0 9 * * mon-fri", "#general", "standup", slackbot.InUserTimezone("U2"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	next, ok := bot.NextRun(id)
+	if !ok {
+		t.Fatal("want the job scheduled")
+	}
+	if _, offset := next.Zone(); offset != 9*60*60 || next.Hour() != 9 || next.Minute() != 0 {
+		t.Errorf("want 9:00 in the user's time zone, got %v", next)
+	}
+	if wd := next.Weekday(); wd == time.Saturday || wd == time.Sunday {
+		t.Errorf("want a weekday, got %v", next)
+	}
+	if !bot.Unschedule(id) || bot.Unschedule(id) {
+		t.Error("want the job unscheduled once")
+	}
+	if _, ok := bot.NextRun(id); ok {
+		t.Error("want no next run of the unscheduled job")
+	}
+
+	nop := func(context.Context) error { return nil }
+	testdata := []struct {
+		name string
+		spec string
+		fn   slackbot.JobFunc
+		opts []slackbot.ScheduleOption
+		want error
+	}{
+		{name: "invalid spec", spec: "0 9 * *", fn: nop},
+		{name: "never matches", spec: "0 0 30 2 *", fn: nop},
+		{name: "nil job", spec: "0 9 * * *"},
+		{name: "nil location", spec: "0 9 * * *", fn: nop, opts: []slackbot.ScheduleOption{slackbot.InLocation(nil)}},
+		{name: "empty user", spec: "0 9 * * *", fn: nop, opts: []slackbot.ScheduleOption{slackbot.InUserTimezone("")}},
+		{name: "unknown user", spec: "0 9 * * *", fn: nop, opts: []slackbot.ScheduleOption{slackbot.InUserTimezone("UX")}, want: webapi.ErrUserNotFound},
+	}
+	for _, tt := range testdata {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := bot.Schedule(ctx, tt.spec, tt.fn, tt.opts...)
+			if err == nil {
+				t.Fatal("want error")
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("want %v, got %v", tt.want, err)
+			}
+		})
+	}
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		if _, err := bot.Schedule(ctx, "0 9 * * *", nop, slackbot.InUserTimezone("U3")); !errors.Is(err, context.Canceled) {
+			t.Errorf("want context.Canceled, got %v", err)
+		}
+	})
+}
+
+func TestClient_RunScheduler(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	bot, err := slackbot.New("xapp-token", "xoxb-token", slackbot.SetBaseURL(srv.URL()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer bot.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- bot.RunScheduler(ctx) }()
+	// the scheduler with the canceled context returns nil at once, unless the other one is running.
+	canceled, stop := context.WithCancel(context.Background())
+	stop()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		err := bot.RunScheduler(canceled)
+		if errors.Is(err, slackbot.ErrSchedulerRunning) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("scheduler did not start")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,170 @@
+package slacktest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// EphemeralMessage is the ephemeral message posted by the client, which is visible only to the user.
+type EphemeralMessage struct {
+	Channel  string          `json:"channel"`
+	User     string          `json:"user"`
+	Text     string          `json:"text"`
+	ThreadTS string          `json:"thread_ts"`
+	TS       string          `json:"-"`
+	Blocks   json.RawMessage `json:"blocks"`
+	Raw      json.RawMessage `json:"-"` // request body
+}
+
+// messageUpdate is the request of chat.update and chat.delete.
+type messageUpdate struct {
+	PostedMessage
+	TS string `json:"ts"`
+}
+
+func (s *Server) chatUpdate(w http.ResponseWriter, r *http.Request) {
+	var m messageUpdate
+	if err := decodeBody(r, &m); err != nil {
+		writeError(w, "invalid_json")
+		return
+	}
+	if m.Text == "" && len(m.Blocks) == 0 {
+		writeError(w, "no_text")
+		return
+	}
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	p := s.findMessage(m.Channel, m.TS)
+	if p == nil {
+		writeError(w, "message_not_found")
+		return
+	}
+	p.Text, p.Blocks, p.Raw = m.Text, m.Blocks, m.Raw
+	if len(m.Metadata) > 0 {
+		p.Metadata = m.Metadata
+	}
+	s.notify()
+	writeJSON(w, map[string]interface{}{
+		"ok":      true,
+		"channel": p.Channel,
+		"ts":      p.TS,
+		"text":    p.Text,
+		"message": p.message(),
+	})
+}
+
+func (s *Server) chatDelete(w http.ResponseWriter, r *http.Request) {
+	var m messageUpdate
+	if err := decodeBody(r, &m); err != nil {
+		writeError(w, "invalid_json")
+		return
+	}
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	for i, v := range s.messages {
+		if v.Channel == m.Channel && v.TS == m.TS {
+			s.messages = append(s.messages[:i:i], s.messages[i+1:]...)
+			s.notify()
+			writeJSON(w, map[string]interface{}{
+				"ok":      true,
+				"channel": m.Channel,
+				"ts":      m.TS,
+			})
+			return
+		}
+	}
+	writeError(w, "message_not_found")
+}
+
+func (s *Server) chatPostEphemeral(w http.ResponseWriter, r *http.Request) {
+	var m EphemeralMessage
+	if err := decodeBody(r, &m); err != nil {
+		writeError(w, "invalid_json")
+		return
+	}
+	if m.Channel == "" {
+		writeError(w, "channel_not_found")
+		return
+	}
+	if m.User == "" {
+		writeError(w, "user_not_in_channel")
+		return
+	}
+	if m.Text == "" && len(m.Blocks) == 0 {
+		writeError(w, "no_text")
+		return
+	}
+	s.mux.Lock()
+	m.TS = s.timestamp()
+	s.ephemerals = append(s.ephemerals, m)
+	s.notify()
+	s.mux.Unlock()
+	writeJSON(w, map[string]interface{}{
+		"ok":         true,
+		"message_ts": m.TS,
+	})
+}
+
+func (s *Server) chatMeMessage(w http.ResponseWriter, r *http.Request) {
+	var m PostedMessage
+	if err := decodeBody(r, &m); err != nil {
+		writeError(w, "invalid_json")
+		return
+	}
+	if m.Channel == "" {
+		writeError(w, "channel_not_found")
+		return
+	}
+	if m.Text == "" {
+		writeError(w, "no_text")
+		return
+	}
+	s.mux.Lock()
+	m.Method = "chat.meMessage"
+	m.TS = s.timestamp()
+	s.messages = append(s.messages, m)
+	s.notify()
+	s.mux.Unlock()
+	writeJSON(w, map[string]interface{}{
+		"ok":      true,
+		"channel": m.Channel,
+		"ts":      m.TS,
+	})
+}
+
+// findMessage returns the posted message of the channel and the timestamp. The caller must hold the lock.
+func (s *Server) findMessage(channel, ts string) *PostedMessage {
+	for i, v := range s.messages {
+		if v.Channel == channel && v.TS == ts {
+			return &s.messages[i]
+		}
+	}
+	return nil
+}
+
+// Message returns the message of the channel and the timestamp posted by the client,
+// as updated by chat.update. It returns false if there is no such message or it was deleted.
+func (s *Server) Message(channel, ts string) (PostedMessage, bool) {
+	defer s.mux.Unlock()
+	s.mux.Lock()
+	if m := s.findMessage(channel, ts); m != nil {
+		return *m, true
+	}
+	return PostedMessage{}, false
+}
+
+// Ephemerals returns the ephemeral messages posted by the client.
+func (s *Server) Ephemerals() []EphemeralMessage {
+	defer s.mux.Unlock()
+	s.mux.Lock()
+	return append([]EphemeralMessage{}, s.ephemerals...)
+}
+
+// WaitEphemerals waits until the client posts n ephemeral messages in total and returns them.
+func (s *Server) WaitEphemerals(ctx context.Context, n int) ([]EphemeralMessage, error) {
+	if err := s.wait(ctx, func() bool { return len(s.ephemerals) >= n }); err != nil {
+		return nil, err
+	}
+	return s.Ephemerals(), nil
+}
@@ -0,0 +1,368 @@
+package slacktest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ikawaha/slackbot/socketmode"
+	"github.com/ikawaha/slackbot/webapi"
+)
+
+// BotUserID is the user ID of the bot on the server, which joins the channels by conversations.join.
+const BotUserID = "UBOT"
+
+// AddChannel adds the conversation to the workspace, or replaces the conversation of the same ID,
+// with the user IDs of its members.
+func (s *Server) AddChannel(ch webapi.Channel, members ...string) {
+	defer s.mux.Unlock()
+	s.mux.Lock()
+	s.putChannel(ch)
+	s.members[ch.ID] = append([]string(nil), members...)
+}
+
+// Channels returns the conversations of the workspace.
+func (s *Server) Channels() []webapi.Channel {
+	defer s.mux.Unlock()
+	s.mux.Lock()
+	return append([]webapi.Channel(nil), s.channels...)
+}
+
+// Members returns the user IDs of the members of the conversation.
+func (s *Server) Members(channelID string) []string {
+	defer s.mux.Unlock()
+	s.mux.Lock()
+	return append([]string(nil), s.members[channelID]...)
+}
+
+// SendChannelEvent applies the change of the channel to the workspace, and sends the event of the type:
+// channel_created, channel_rename, channel_archive, channel_unarchive or channel_deleted.
+// It returns the envelope ID.
+func (s *Server) SendChannelEvent(t socketmode.EventType, ch webapi.Channel) (string, error) {
+	s.mux.Lock()
+	var channel interface{} = ch.ID
+	switch t {
+	case socketmode.ChannelCreated, socketmode.ChannelRename:
+		if i, ok := s.channel(ch.ID); ok {
+			s.channels[i].Name = ch.Name
+			s.channels[i].NameNormalized = ch.Name
+		} else {
+			s.putChannel(ch)
+		}
+		channel = map[string]interface{}{
+			"id":      ch.ID,
+			"name":    ch.Name,
+			"created": ch.Created,
+			"creator": ch.Creator,
+		}
+	case socketmode.ChannelArchive, socketmode.ChannelUnarchive:
+		if i, ok := s.channel(ch.ID); ok {
+			s.channels[i].IsArchived = t == socketmode.ChannelArchive
+		}
+	case socketmode.ChannelDeleted:
+		if i, ok := s.channel(ch.ID); ok {
+			s.channels = append(s.channels[:i], s.channels[i+1:]...)
+			delete(s.members, ch.ID)
+		}
+	}
+	ts := s.timestamp()
+	s.mux.Unlock()
+	return s.sendEvent("", map[string]interface{}{
+		"type":     t,
+		"channel":  channel,
+		"event_ts": ts,
+	})
+}
+
+// putChannel adds or replaces the channel. The caller must hold the lock.
+func (s *Server) putChannel(ch webapi.Channel) {
+	for i, v := range s.channels {
+		if v.ID == ch.ID {
+			s.channels[i] = ch
+			return
+		}
+	}
+	s.channels = append(s.channels, ch)
+}
+
+// channel returns the index of the channel. The caller must hold the lock.
+func (s *Server) channel(id string) (int, bool) {
+	for i, v := range s.channels {
+		if v.ID == id {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// channelType returns the conversation type of the channel.
+func channelType(ch webapi.Channel) webapi.ConversationType {
+	switch {
+	case ch.IsIM:
+		return webapi.IM
+	case ch.IsMPIM:
+		return webapi.MPIM
+	case ch.IsPrivate:
+		return webapi.PrivateChannel
+	}
+	return webapi.PublicChannel
+}
+
+// channelView returns the channel as the bot sees it. The caller must hold the lock.
+func (s *Server) channelView(ch webapi.Channel) webapi.Channel {
+	ch.NumMembers = len(s.members[ch.ID])
+	ch.IsMember = contains(s.members[ch.ID], BotUserID)
+	return ch
+}
+
+func contains(list []string, v string) bool {
+	for _, x := range list {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) conversationsList(w http.ResponseWriter, r *http.Request) {
+	types := map[webapi.ConversationType]bool{}
+	for _, v := range strings.Split(r.FormValue("types"), ",") {
+		if v != "" {
+			types[webapi.ConversationType(v)] = true
+		}
+	}
+	if len(types) == 0 {
+		types[webapi.PublicChannel] = true
+	}
+	excludeArchived := r.FormValue("exclude_archived") == "true"
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	var list []webapi.Channel
+	for _, v := range s.channels {
+		if types[channelType(v)] && !(excludeArchived && v.IsArchived) {
+			list = append(list, s.channelView(v))
+		}
+	}
+	start, end, next, ok := paginate(r, len(list))
+	if !ok {
+		writeError(w, "invalid_cursor")
+		return
+	}
+	writeJSON(w, map[string]interface{}{
+		"ok":       true,
+		"channels": list[start:end],
+		"response_metadata": map[string]interface{}{
+			"next_cursor": next,
+		},
+	})
+}
+
+func (s *Server) conversationsInfo(w http.ResponseWriter, r *http.Request) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	i, ok := s.channel(r.FormValue("channel"))
+	if !ok {
+		writeError(w, "channel_not_found")
+		return
+	}
+	writeChannel(w, s.channelView(s.channels[i]))
+}
+
+func (s *Server) conversationsMembers(w http.ResponseWriter, r *http.Request) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	i, ok := s.channel(r.FormValue("channel"))
+	if !ok {
+		writeError(w, "channel_not_found")
+		return
+	}
+	members := s.members[s.channels[i].ID]
+	start, end, next, ok := paginate(r, len(members))
+	if !ok {
+		writeError(w, "invalid_cursor")
+		return
+	}
+	writeJSON(w, map[string]interface{}{
+		"ok":      true,
+		"members": members[start:end],
+		"response_metadata": map[string]interface{}{
+			"next_cursor": next,
+		},
+	})
+}
+
+func (s *Server) conversationsJoin(w http.ResponseWriter, r *http.Request) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	i, ok := s.channel(r.FormValue("channel"))
+	if !ok {
+		writeError(w, "channel_not_found")
+		return
+	}
+	ch := s.channels[i]
+	switch {
+	case ch.IsArchived:
+		writeError(w, "is_archived")
+		return
+	case channelType(ch) != webapi.PublicChannel:
+		writeError(w, "method_not_supported_for_channel_type")
+		return
+	}
+	if !contains(s.members[ch.ID], BotUserID) {
+		s.members[ch.ID] = append(s.members[ch.ID], BotUserID)
+	}
+	writeChannel(w, s.channelView(ch))
+}
+
+func (s *Server) conversationsOpen(w http.ResponseWriter, r *http.Request) {
+	users := strings.Split(r.FormValue("users"), ",")
+	if len(users) == 0 || users[0] == "" {
+		writeError(w, "users_list_not_supplied")
+		return
+	}
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	for _, u := range users {
+		if !s.hasUser(u) {
+			writeError(w, "user_not_found")
+			return
+		}
+	}
+	members := append([]string{BotUserID}, users...)
+	for _, v := range s.channels {
+		if (v.IsIM || v.IsMPIM) && sameMembers(s.members[v.ID], members) {
+			writeChannel(w, s.channelView(v))
+			return
+		}
+	}
+	s.seq++
+	ch := webapi.Channel{
+		ID:      fmt.Sprintf("D%06d", s.seq),
+		Created: time.Now().Unix(),
+	}
+	if len(users) == 1 {
+		ch.IsIM = true
+		ch.User = users[0]
+	} else {
+		ch.IsMPIM = true
+		ch.IsPrivate = true
+		ch.ID = fmt.Sprintf("G%06d", s.seq)
+		ch.Name = "mpdm-" + strings.Join(users, "--")
+	}
+	s.putChannel(ch)
+	s.members[ch.ID] = members
+	writeChannel(w, s.channelView(ch))
+}
+
+func (s *Server) hasUser(id string) bool {
+	for _, v := range s.users {
+		if v.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func sameMembers(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, v := range b {
+		if !contains(a, v) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Server) conversationsCreate(w http.ResponseWriter, r *http.Request) {
+	name := r.FormValue("name")
+	if name == "" {
+		writeError(w, "invalid_name_required")
+		return
+	}
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	for _, v := range s.channels {
+		if v.Name == name {
+			writeError(w, "name_taken")
+			return
+		}
+	}
+	s.seq++
+	ch := webapi.Channel{
+		ID:             fmt.Sprintf("C%06d", s.seq),
+		Name:           name,
+		NameNormalized: name,
+		IsChannel:      true,
+		IsPrivate:      r.FormValue("is_private") == "true",
+		Created:        time.Now().Unix(),
+		Creator:        BotUserID,
+	}
+	if ch.IsPrivate {
+		ch.IsChannel = false
+		ch.IsGroup = true
+		ch.ID = fmt.Sprintf("G%06d", s.seq)
+	}
+	s.putChannel(ch)
+	s.members[ch.ID] = []string{BotUserID}
+	writeChannel(w, s.channelView(ch))
+}
+
+func (s *Server) conversationsInvite(w http.ResponseWriter, r *http.Request) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	i, ok := s.channel(r.FormValue("channel"))
+	if !ok {
+		writeError(w, "channel_not_found")
+		return
+	}
+	ch := s.channels[i]
+	if ch.IsArchived {
+		writeError(w, "is_archived")
+		return
+	}
+	if !contains(s.members[ch.ID], BotUserID) {
+		writeError(w, "not_in_channel")
+		return
+	}
+	users := strings.Split(r.FormValue("users"), ",")
+	for _, u := range users {
+		switch {
+		case !s.hasUser(u):
+			writeError(w, "user_not_found")
+			return
+		case contains(s.members[ch.ID], u):
+			writeError(w, "already_in_channel")
+			return
+		}
+	}
+	s.members[ch.ID] = append(s.members[ch.ID], users...)
+	writeChannel(w, s.channelView(ch))
+}
+
+func (s *Server) conversationsArchive(w http.ResponseWriter, r *http.Request) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	i, ok := s.channel(r.FormValue("channel"))
+	if !ok {
+		writeError(w, "channel_not_found")
+		return
+	}
+	if s.channels[i].IsArchived {
+		writeError(w, "already_archived")
+		return
+	}
+	s.channels[i].IsArchived = true
+	writeJSON(w, map[string]interface{}{
+		"ok": true,
+	})
+}
+
+func writeChannel(w http.ResponseWriter, ch webapi.Channel) {
+	writeJSON(w, map[string]interface{}{
+		"ok":      true,
+		"channel": ch,
+	})
+}
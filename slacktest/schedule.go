@@ -0,0 +1,120 @@
+package slacktest
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ikawaha/slackbot/webapi"
+)
+
+// scheduleRequest is the request of chat.scheduleMessage.
+type scheduleRequest struct {
+	PostedMessage
+	PostAt int64 `json:"post_at"`
+}
+
+func (s *Server) chatScheduleMessage(w http.ResponseWriter, r *http.Request) {
+	var m scheduleRequest
+	if err := decodeBody(r, &m); err != nil {
+		writeError(w, "invalid_json")
+		return
+	}
+	if m.Channel == "" {
+		writeError(w, "channel_not_found")
+		return
+	}
+	if m.Text == "" && len(m.Blocks) == 0 {
+		writeError(w, "no_text")
+		return
+	}
+	now := time.Now()
+	if m.PostAt <= now.Unix() {
+		writeError(w, "time_in_past")
+		return
+	}
+	if m.PostAt > now.AddDate(0, 0, 120).Unix() {
+		writeError(w, "time_too_far")
+		return
+	}
+	s.mux.Lock()
+	s.seq++
+	v := webapi.ScheduledMessage{
+		ID:          fmt.Sprintf("Q%06d", s.seq),
+		ChannelID:   m.Channel,
+		PostAt:      m.PostAt,
+		DateCreated: now.Unix(),
+		Text:        m.Text,
+	}
+	s.scheduled = append(s.scheduled, v)
+	s.notify()
+	s.mux.Unlock()
+	writeJSON(w, map[string]interface{}{
+		"ok":                   true,
+		"channel":              v.ChannelID,
+		"scheduled_message_id": v.ID,
+		"post_at":              v.PostAt,
+		"message": map[string]interface{}{
+			"type": "message",
+			"text": v.Text,
+		},
+	})
+}
+
+func (s *Server) chatScheduledMessagesList(w http.ResponseWriter, r *http.Request) {
+	channel := r.FormValue("channel")
+	oldest, _ := strconv.ParseInt(r.FormValue("oldest"), 10, 64)
+	latest, _ := strconv.ParseInt(r.FormValue("latest"), 10, 64)
+	s.mux.Lock()
+	var list []webapi.ScheduledMessage
+	for _, v := range s.scheduled {
+		if channel != "" && v.ChannelID != channel || oldest > 0 && v.PostAt < oldest || latest > 0 && v.PostAt > latest {
+			continue
+		}
+		list = append(list, v)
+	}
+	s.mux.Unlock()
+	start, end, next, ok := paginate(r, len(list))
+	if !ok {
+		writeError(w, "invalid_cursor")
+		return
+	}
+	writeJSON(w, map[string]interface{}{
+		"ok":                 true,
+		"scheduled_messages": append([]webapi.ScheduledMessage{}, list[start:end]...),
+		"response_metadata": map[string]interface{}{
+			"next_cursor": next,
+		},
+	})
+}
+
+func (s *Server) chatDeleteScheduledMessage(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Channel            string `json:"channel"`
+		ScheduledMessageID string `json:"scheduled_message_id"`
+	}
+	if err := decodeBody(r, &req); err != nil {
+		writeError(w, "invalid_json")
+		return
+	}
+	defer s.mux.Unlock()
+	s.mux.Lock()
+	for i, v := range s.scheduled {
+		if v.ID == req.ScheduledMessageID && v.ChannelID == req.Channel {
+			s.scheduled = append(s.scheduled[:i:i], s.scheduled[i+1:]...)
+			s.notify()
+			writeJSON(w, map[string]interface{}{"ok": true})
+			return
+		}
+	}
+	writeError(w, "invalid_scheduled_message_id")
+}
+
+// ScheduledMessages returns the messages scheduled by the client and not deleted.
+// The server does not send them at the scheduled time.
+func (s *Server) ScheduledMessages() []webapi.ScheduledMessage {
+	defer s.mux.Unlock()
+	s.mux.Lock()
+	return append([]webapi.ScheduledMessage{}, s.scheduled...)
+}
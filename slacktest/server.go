@@ -0,0 +1,195 @@
+// Package slacktest implements an in-process fake Slack server to test bots end to end without real tokens.
+//
+// The server implements apps.connections.open, the socket mode handshake and the Web API methods
+// called by the library. Tests inject events through the socket mode connection and assert on
+// the recorded messages, uploads, views and command responses.
+//
+//	srv := slacktest.NewServer()
+//	defer srv.Close()
+//	bot, err := slackbot.New("xapp-token", "xoxb-token", slackbot.SetBaseURL(srv.URL()))
+//	...
+//	srv.SendEvent(socketmode.Event{Type: "message", Channel: "C1", UserID: "U1", Text: "hello"})
+//	msgs, err := srv.WaitPostedMessages(ctx, 1)
+package slacktest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	"github.com/ikawaha/slackbot/webapi"
+)
+
+// DefaultWaitTimeout is the time to wait for a client to connect before sending an envelope.
+const DefaultWaitTimeout = 5 * time.Second
+
+const (
+	apiPath      = "/api/"
+	socketPath   = "/link"
+	commandsPath = "/commands/"
+)
+
+// Server is a fake Slack server.
+type Server struct {
+	server *httptest.Server
+
+	mux              sync.Mutex
+	changed          chan struct{}
+	conns            []*websocket.Conn
+	connects         int
+	next             int
+	seq              int
+	handlers         map[string]http.HandlerFunc
+	errors           map[string]string
+	users            []webapi.User
+	channels         []webapi.Channel
+	members          map[string][]string
+	messages         []PostedMessage
+	history          []PostedMessage
+	ephemerals       []EphemeralMessage
+	scheduled        []webapi.ScheduledMessage
+	uploads          []Upload
+	views            []View
+	commandResponses []CommandResponse
+	acks             []Ack
+}
+
+// NewServer starts a fake Slack server.
+func NewServer() *Server {
+	s := &Server{
+		changed:  make(chan struct{}),
+		handlers: map[string]http.HandlerFunc{},
+		errors:   map[string]string{},
+		members:  map[string][]string{},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(apiPath, s.serveAPI)
+	mux.Handle(socketPath, websocket.Handler(s.serveSocket))
+	mux.HandleFunc(commandsPath, s.serveCommandResponse)
+	s.server = httptest.NewServer(mux)
+	s.registerMethods()
+	return s
+}
+
+// URL returns the base URL of the Web API of the server.
+// Pass it to the BaseURL options of the clients.
+func (s *Server) URL() string {
+	return s.server.URL + apiPath
+}
+
+// Close closes the socket mode connections and shuts down the server.
+func (s *Server) Close() {
+	s.CloseConnections()
+	s.server.Close()
+}
+
+// HandleMethod registers the handler of the Web API method, which overrides the built-in one.
+func (s *Server) HandleMethod(method string, h http.HandlerFunc) {
+	defer s.mux.Unlock()
+	s.mux.Lock()
+	s.handlers[method] = h
+}
+
+// SetError makes the Web API method respond with the error code.
+// An empty code clears the error.
+func (s *Server) SetError(method, code string) {
+	defer s.mux.Unlock()
+	s.mux.Lock()
+	if code == "" {
+		delete(s.errors, method)
+		return
+	}
+	s.errors[method] = code
+}
+
+// AddUser adds the user to the members of the workspace, or replaces the member of the same ID.
+func (s *Server) AddUser(u webapi.User) {
+	defer s.mux.Unlock()
+	s.mux.Lock()
+	for i, v := range s.users {
+		if v.ID == u.ID {
+			s.users[i] = u
+			return
+		}
+	}
+	s.users = append(s.users, u)
+}
+
+func (s *Server) serveAPI(w http.ResponseWriter, r *http.Request) {
+	method := strings.TrimPrefix(r.URL.Path, apiPath)
+	if !authorized(r) {
+		writeError(w, "not_authed")
+		return
+	}
+	s.mux.Lock()
+	code := s.errors[method]
+	h, ok := s.handlers[method]
+	s.mux.Unlock()
+	if code != "" {
+		writeError(w, code)
+		return
+	}
+	if !ok {
+		writeError(w, "unknown_method")
+		return
+	}
+	h(w, r)
+}
+
+func authorized(r *http.Request) bool {
+	if strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+		return true
+	}
+	return r.FormValue("token") != ""
+}
+
+// notify wakes up the waiters. The caller must hold the lock.
+func (s *Server) notify() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// wait waits until the condition, evaluated under the lock, holds.
+func (s *Server) wait(ctx context.Context, cond func() bool) error {
+	for {
+		s.mux.Lock()
+		ok := cond()
+		ch := s.changed
+		s.mux.Unlock()
+		if ok {
+			return nil
+		}
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func writeError(w http.ResponseWriter, code string) {
+	writeJSON(w, map[string]interface{}{
+		"ok":    false,
+		"error": code,
+	})
+}
+
+// timestamp returns a new message timestamp. The caller must hold the lock.
+func (s *Server) timestamp() string {
+	s.seq++
+	return fmt.Sprintf("%d.%06d", time.Now().Unix(), s.seq)
+}
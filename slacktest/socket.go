@@ -0,0 +1,266 @@
+package slacktest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/websocket"
+
+	"github.com/ikawaha/slackbot/socketmode"
+	"github.com/ikawaha/slackbot/webapi"
+)
+
+// Ack is the acknowledgement received from the client.
+type Ack struct {
+	EnvelopeID string          `json:"envelope_id"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// SlashCommand is the slash command invocation to send to the client.
+type SlashCommand struct {
+	Command     string
+	Text        string
+	UserID      string
+	UserName    string
+	ChannelID   string
+	ChannelName string
+	TeamID      string
+	APIAppID    string
+	TriggerID   string
+	ResponseURL string // generated if empty
+}
+
+// ErrNoConnection is returned when no client connects to the server in time.
+var ErrNoConnection = errors.New("no socket mode connection")
+
+func (s *Server) serveSocket(ws *websocket.Conn) {
+	s.mux.Lock()
+	s.conns = append(s.conns, ws)
+	s.connects++
+	n := len(s.conns)
+	s.notify()
+	s.mux.Unlock()
+	defer s.removeConn(ws)
+
+	hello := map[string]interface{}{
+		"type":            string(socketmode.Hello),
+		"num_connections": n,
+	}
+	if err := websocket.JSON.Send(ws, hello); err != nil {
+		return
+	}
+	for {
+		var ack Ack
+		if err := websocket.JSON.Receive(ws, &ack); err != nil {
+			return
+		}
+		s.mux.Lock()
+		s.acks = append(s.acks, ack)
+		s.notify()
+		s.mux.Unlock()
+	}
+}
+
+func (s *Server) removeConn(ws *websocket.Conn) {
+	_ = ws.Close()
+	defer s.mux.Unlock()
+	s.mux.Lock()
+	for i, v := range s.conns {
+		if v == ws {
+			s.conns = append(s.conns[:i], s.conns[i+1:]...)
+			break
+		}
+	}
+	s.notify()
+}
+
+// NumConnections returns the number of open socket mode connections.
+func (s *Server) NumConnections() int {
+	defer s.mux.Unlock()
+	s.mux.Lock()
+	return len(s.conns)
+}
+
+// Connects returns the number of the socket mode connections opened so far, including the closed ones.
+func (s *Server) Connects() int {
+	defer s.mux.Unlock()
+	s.mux.Lock()
+	return s.connects
+}
+
+// WaitConnects waits until the client has opened the connections at least connects times in total,
+// and exactly open connections are open, e.g. to wait for the client to refresh or reconnect.
+func (s *Server) WaitConnects(ctx context.Context, connects, open int) error {
+	return s.wait(ctx, func() bool {
+		return s.connects >= connects && len(s.conns) == open
+	})
+}
+
+// WaitConnections waits until the number of open socket mode connections reaches n.
+func (s *Server) WaitConnections(ctx context.Context, n int) error {
+	return s.wait(ctx, func() bool {
+		return len(s.conns) >= n
+	})
+}
+
+// CloseConnections closes all the socket mode connections abruptly, as if the network failed.
+func (s *Server) CloseConnections() {
+	s.mux.Lock()
+	conns := append([]*websocket.Conn{}, s.conns...)
+	s.mux.Unlock()
+	for _, v := range conns {
+		_ = v.Close()
+	}
+}
+
+// SendEnvelope sends the envelope to one of the socket mode connections in turn.
+// If the envelope ID is empty and the envelope is not a hello or disconnect envelope,
+// a new ID is assigned. SendEnvelope returns the envelope ID.
+func (s *Server) SendEnvelope(el socketmode.Envelope) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultWaitTimeout)
+	defer cancel()
+	if err := s.WaitConnections(ctx, 1); err != nil {
+		return "", ErrNoConnection
+	}
+	s.mux.Lock()
+	if len(s.conns) == 0 {
+		s.mux.Unlock()
+		return "", ErrNoConnection
+	}
+	ws := s.conns[s.next%len(s.conns)]
+	s.next++
+	if el.EnvelopeID == "" {
+		switch socketmode.EnvelopeType(el.Type) {
+		case socketmode.Hello, socketmode.Disconnect:
+		default:
+			s.seq++
+			el.EnvelopeID = fmt.Sprintf("envelope-%d", s.seq)
+		}
+	}
+	s.mux.Unlock()
+	if err := websocket.JSON.Send(ws, el); err != nil {
+		return "", fmt.Errorf("send envelope error: %w", err)
+	}
+	return el.EnvelopeID, nil
+}
+
+// SendEvent sends the event as an events_api envelope and returns the envelope ID.
+func (s *Server) SendEvent(e socketmode.Event) (string, error) {
+	return s.sendEvent(e.TeamID, e)
+}
+
+// SendUserEvent adds or updates the user of the server, and sends the user_change or team_join event
+// which has the user object. It returns the envelope ID.
+func (s *Server) SendUserEvent(t socketmode.EventType, u webapi.User) (string, error) {
+	s.AddUser(u)
+	s.mux.Lock()
+	ts := s.timestamp()
+	s.mux.Unlock()
+	return s.sendEvent(u.TeamID, map[string]interface{}{
+		"type":     t,
+		"user":     u,
+		"event_ts": ts,
+	})
+}
+
+func (s *Server) sendEvent(teamID string, e interface{}) (string, error) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return "", err
+	}
+	p, err := json.Marshal(map[string]interface{}{
+		"type":    "event_callback",
+		"team_id": teamID,
+		"event":   json.RawMessage(b),
+	})
+	if err != nil {
+		return "", err
+	}
+	return s.SendEnvelope(socketmode.Envelope{
+		Type:    string(socketmode.EventsAPI),
+		Payload: p,
+	})
+}
+
+// SendSlashCommand sends the slash command as a slash_commands envelope and returns the envelope ID.
+// If the response URL of the command is empty, a URL of the server is assigned,
+// and the responses to it are recorded as the command responses.
+func (s *Server) SendSlashCommand(cmd SlashCommand) (string, error) {
+	if cmd.ResponseURL == "" {
+		s.mux.Lock()
+		s.seq++
+		cmd.ResponseURL = fmt.Sprintf("%s%s%d", s.server.URL, commandsPath, s.seq)
+		s.mux.Unlock()
+	}
+	if !strings.HasPrefix(cmd.Command, "/") {
+		cmd.Command = "/" + cmd.Command
+	}
+	p, err := json.Marshal(map[string]string{
+		"command":      cmd.Command,
+		"text":         cmd.Text,
+		"user_id":      cmd.UserID,
+		"user_name":    cmd.UserName,
+		"channel_id":   cmd.ChannelID,
+		"channel_name": cmd.ChannelName,
+		"team_id":      cmd.TeamID,
+		"api_app_id":   cmd.APIAppID,
+		"trigger_id":   cmd.TriggerID,
+		"response_url": cmd.ResponseURL,
+	})
+	if err != nil {
+		return "", err
+	}
+	return s.SendEnvelope(socketmode.Envelope{
+		Type:                   string(socketmode.SlashCommands),
+		Payload:                p,
+		AcceptsResponsePayload: true,
+	})
+}
+
+// SendInteraction sends the interaction payload as an interactive envelope and returns the envelope ID.
+func (s *Server) SendInteraction(p socketmode.InteractionPayload) (string, error) {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	return s.SendEnvelope(socketmode.Envelope{
+		Type:                   string(socketmode.Interactive),
+		Payload:                b,
+		AcceptsResponsePayload: socketmode.EventType(p.Type) == socketmode.ViewSubmission,
+	})
+}
+
+// SendDisconnect sends the disconnect envelope with the reason.
+func (s *Server) SendDisconnect(reason socketmode.DisconnectReason) error {
+	_, err := s.SendEnvelope(socketmode.Envelope{
+		Type:      string(socketmode.Disconnect),
+		Reason:    string(reason),
+		DebugInfo: json.RawMessage(`{"host":"slacktest"}`),
+	})
+	return err
+}
+
+// Acks returns the acknowledgements received from the client.
+func (s *Server) Acks() []Ack {
+	defer s.mux.Unlock()
+	s.mux.Lock()
+	return append([]Ack{}, s.acks...)
+}
+
+// WaitAck waits for the acknowledgement of the envelope.
+func (s *Server) WaitAck(ctx context.Context, envelopeID string) (Ack, error) {
+	var ret Ack
+	err := s.wait(ctx, func() bool {
+		for _, v := range s.acks {
+			if v.EnvelopeID == envelopeID {
+				ret = v
+				return true
+			}
+		}
+		return false
+	})
+	return ret, err
+}
@@ -0,0 +1,495 @@
+package slacktest
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// maxPageLimit is the number of items in a page when the request has no limit.
+	maxPageLimit = 1000
+	cursorPrefix = "offset:"
+)
+
+// PostedMessage is the message posted by the client.
+type PostedMessage struct {
+	Method   string          `json:"-"`
+	Channel  string          `json:"channel"`
+	User     string          `json:"-"` // the author of the message added by AddMessage
+	Text     string          `json:"text"`
+	ThreadTS string          `json:"thread_ts"`
+	TS       string          `json:"-"`
+	Blocks   json.RawMessage `json:"blocks"`
+	Metadata json.RawMessage `json:"metadata"`
+	Raw      json.RawMessage `json:"-"` // request body
+}
+
+// Upload is the file uploaded by the client.
+type Upload struct {
+	Channels       []string
+	Title          string
+	FileName       string
+	FileType       string
+	InitialComment string
+	Content        []byte
+}
+
+// View is the view opened, pushed or updated by the client.
+type View struct {
+	Method    string          `json:"-"`
+	ID        string          `json:"-"`
+	TriggerID string          `json:"trigger_id"`
+	ViewID    string          `json:"view_id"`
+	Hash      string          `json:"hash"`
+	View      json.RawMessage `json:"view"`
+}
+
+// CommandResponse is the response to a slash command posted to its response URL.
+type CommandResponse struct {
+	URL          string          `json:"-"`
+	ResponseType string          `json:"response_type"`
+	Text         string          `json:"text"`
+	Blocks       json.RawMessage `json:"blocks"`
+	Raw          json.RawMessage `json:"-"` // request body
+}
+
+func (s *Server) registerMethods() {
+	s.handlers["apps.connections.open"] = s.appsConnectionsOpen
+	s.handlers["chat.postMessage"] = s.chatPostMessage
+	s.handlers["chat.update"] = s.chatUpdate
+	s.handlers["chat.delete"] = s.chatDelete
+	s.handlers["chat.postEphemeral"] = s.chatPostEphemeral
+	s.handlers["chat.meMessage"] = s.chatMeMessage
+	s.handlers["chat.scheduleMessage"] = s.chatScheduleMessage
+	s.handlers["chat.scheduledMessages.list"] = s.chatScheduledMessagesList
+	s.handlers["chat.deleteScheduledMessage"] = s.chatDeleteScheduledMessage
+	s.handlers["files.upload"] = s.filesUpload
+	s.handlers["users.list"] = s.usersList
+	s.handlers["users.info"] = s.usersInfo
+	s.handlers["conversations.history"] = s.conversationsHistory
+	s.handlers["conversations.replies"] = s.conversationsReplies
+	s.handlers["conversations.list"] = s.conversationsList
+	s.handlers["conversations.info"] = s.conversationsInfo
+	s.handlers["conversations.members"] = s.conversationsMembers
+	s.handlers["conversations.join"] = s.conversationsJoin
+	s.handlers["conversations.open"] = s.conversationsOpen
+	s.handlers["conversations.create"] = s.conversationsCreate
+	s.handlers["conversations.invite"] = s.conversationsInvite
+	s.handlers["conversations.archive"] = s.conversationsArchive
+	s.handlers["views.open"] = s.viewsMethod("views.open")
+	s.handlers["views.push"] = s.viewsMethod("views.push")
+	s.handlers["views.update"] = s.viewsMethod("views.update")
+}
+
+func (s *Server) appsConnectionsOpen(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]interface{}{
+		"ok":  true,
+		"url": "ws" + strings.TrimPrefix(s.server.URL, "http") + socketPath,
+	})
+}
+
+func (s *Server) chatPostMessage(w http.ResponseWriter, r *http.Request) {
+	var m PostedMessage
+	if err := decodeBody(r, &m); err != nil {
+		writeError(w, "invalid_json")
+		return
+	}
+	if m.Channel == "" {
+		writeError(w, "channel_not_found")
+		return
+	}
+	if m.Text == "" && len(m.Blocks) == 0 {
+		writeError(w, "no_text")
+		return
+	}
+	s.mux.Lock()
+	m.Method = "chat.postMessage"
+	m.TS = s.timestamp()
+	s.messages = append(s.messages, m)
+	s.notify()
+	s.mux.Unlock()
+	writeJSON(w, map[string]interface{}{
+		"ok":      true,
+		"channel": m.Channel,
+		"ts":      m.TS,
+		"message": map[string]interface{}{
+			"type": "message",
+			"text": m.Text,
+			"ts":   m.TS,
+		},
+	})
+}
+
+// message returns the posted message as a message object of the Web API.
+func (m PostedMessage) message() map[string]interface{} {
+	ret := map[string]interface{}{
+		"type": "message",
+		"text": m.Text,
+		"ts":   m.TS,
+	}
+	if m.User != "" {
+		ret["user"] = m.User
+	}
+	if m.ThreadTS != "" {
+		ret["thread_ts"] = m.ThreadTS
+	}
+	if len(m.Blocks) > 0 {
+		ret["blocks"] = m.Blocks
+	}
+	if len(m.Metadata) > 0 {
+		ret["metadata"] = m.Metadata
+	}
+	return ret
+}
+
+func (s *Server) conversationsHistory(w http.ResponseWriter, r *http.Request) {
+	channel := r.FormValue("channel")
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	var messages []map[string]interface{}
+	for _, m := range s.conversation() {
+		if m.Channel == channel && (m.ThreadTS == "" || m.ThreadTS == m.TS) && inRange(r, m.TS) {
+			messages = append(messages, s.historyMessage(m))
+		}
+	}
+	// the newest first
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+	writeMessages(w, r, messages)
+}
+
+func (s *Server) conversationsReplies(w http.ResponseWriter, r *http.Request) {
+	channel, ts := r.FormValue("channel"), r.FormValue("ts")
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	var messages []map[string]interface{}
+	for _, m := range s.conversation() {
+		// the parent message is always included.
+		if m.Channel == channel && (m.TS == ts || m.ThreadTS == ts && inRange(r, m.TS)) {
+			messages = append(messages, s.historyMessage(m))
+		}
+	}
+	if len(messages) == 0 {
+		writeError(w, "thread_not_found")
+		return
+	}
+	writeMessages(w, r, messages)
+}
+
+// conversation returns the posted and the added messages in the order of the timestamps.
+// The caller must hold the lock.
+func (s *Server) conversation() []PostedMessage {
+	ret := append(append([]PostedMessage{}, s.messages...), s.history...)
+	sort.SliceStable(ret, func(i, j int) bool {
+		return lessTS(ret[i].TS, ret[j].TS)
+	})
+	return ret
+}
+
+// historyMessage returns the message with the replies of the thread. The caller must hold the lock.
+func (s *Server) historyMessage(m PostedMessage) map[string]interface{} {
+	ret := m.message()
+	if m.ThreadTS != "" && m.ThreadTS != m.TS {
+		return ret
+	}
+	var replies int
+	for _, v := range s.conversation() {
+		if v.Channel == m.Channel && v.ThreadTS == m.TS && v.TS != m.TS {
+			replies++
+		}
+	}
+	if replies > 0 {
+		ret["thread_ts"] = m.TS
+		ret["reply_count"] = replies
+	}
+	return ret
+}
+
+// inRange returns true, if the timestamp is between the oldest and the latest of the request.
+func inRange(r *http.Request, ts string) bool {
+	inclusive := r.FormValue("inclusive") == "true"
+	if v := r.FormValue("oldest"); v != "" && (lessTS(ts, v) || !inclusive && ts == v) {
+		return false
+	}
+	if v := r.FormValue("latest"); v != "" && (lessTS(v, ts) || !inclusive && ts == v) {
+		return false
+	}
+	return true
+}
+
+// lessTS compares the message timestamps, "seconds.micros".
+func lessTS(a, b string) bool {
+	as, af := splitTS(a)
+	bs, bf := splitTS(b)
+	if as != bs {
+		return as < bs
+	}
+	return af < bf
+}
+
+func splitTS(ts string) (int64, int64) {
+	sec, frac := ts, ""
+	if i := strings.Index(ts, "."); i >= 0 {
+		sec, frac = ts[:i], ts[i+1:]
+	}
+	for len(frac) < 6 {
+		frac += "0"
+	}
+	s, _ := strconv.ParseInt(sec, 10, 64)
+	f, _ := strconv.ParseInt(frac, 10, 64)
+	return s, f
+}
+
+func writeMessages(w http.ResponseWriter, r *http.Request, messages []map[string]interface{}) {
+	start, end, next, ok := paginate(r, len(messages))
+	if !ok {
+		writeError(w, "invalid_cursor")
+		return
+	}
+	writeJSON(w, map[string]interface{}{
+		"ok":       true,
+		"messages": messages[start:end],
+		"has_more": next != "",
+		"response_metadata": map[string]interface{}{
+			"next_cursor": next,
+		},
+	})
+}
+
+func (s *Server) filesUpload(w http.ResponseWriter, r *http.Request) {
+	f, h, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, "no_file_data")
+		return
+	}
+	defer f.Close()
+	b, err := io.ReadAll(f)
+	if err != nil {
+		writeError(w, "file_upload_failed")
+		return
+	}
+	u := Upload{
+		Title:          r.FormValue("title"),
+		FileName:       h.Filename,
+		FileType:       r.FormValue("filetype"),
+		InitialComment: r.FormValue("initial_comment"),
+		Content:        b,
+	}
+	if v := r.FormValue("channels"); v != "" {
+		u.Channels = strings.Split(v, ",")
+	}
+	s.mux.Lock()
+	s.uploads = append(s.uploads, u)
+	s.notify()
+	s.mux.Unlock()
+	writeJSON(w, map[string]interface{}{
+		"ok": true,
+		"file": map[string]interface{}{
+			"name":  u.FileName,
+			"title": u.Title,
+		},
+	})
+}
+
+func (s *Server) usersList(w http.ResponseWriter, r *http.Request) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	start, end, next, ok := paginate(r, len(s.users))
+	if !ok {
+		writeError(w, "invalid_cursor")
+		return
+	}
+	writeJSON(w, map[string]interface{}{
+		"ok":      true,
+		"members": s.users[start:end],
+		"response_metadata": map[string]interface{}{
+			"next_cursor": next,
+		},
+	})
+}
+
+func (s *Server) usersInfo(w http.ResponseWriter, r *http.Request) {
+	id := r.FormValue("user")
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	for _, v := range s.users {
+		if v.ID == id {
+			writeJSON(w, map[string]interface{}{
+				"ok":   true,
+				"user": v,
+			})
+			return
+		}
+	}
+	writeError(w, "user_not_found")
+}
+
+func (s *Server) viewsMethod(method string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var v View
+		if err := decodeBody(r, &v); err != nil {
+			writeError(w, "invalid_json")
+			return
+		}
+		s.mux.Lock()
+		v.Method = method
+		v.ID = v.ViewID
+		if v.ID == "" {
+			s.seq++
+			v.ID = fmt.Sprintf("V%06d", s.seq)
+		}
+		s.views = append(s.views, v)
+		s.notify()
+		s.mux.Unlock()
+		writeJSON(w, map[string]interface{}{
+			"ok": true,
+			"view": map[string]interface{}{
+				"id":   v.ID,
+				"hash": v.ID + "-hash",
+			},
+		})
+	}
+}
+
+func (s *Server) serveCommandResponse(w http.ResponseWriter, r *http.Request) {
+	var resp CommandResponse
+	if err := decodeBody(r, &resp); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	resp.URL = s.server.URL + r.URL.Path
+	s.mux.Lock()
+	s.commandResponses = append(s.commandResponses, resp)
+	s.notify()
+	s.mux.Unlock()
+	w.WriteHeader(http.StatusOK)
+}
+
+// decodeBody decodes the JSON request body into v and keeps the raw body if v has the Raw field.
+func decodeBody(r *http.Request, v interface{}) error {
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return err
+	}
+	switch t := v.(type) {
+	case *PostedMessage:
+		t.Raw = b
+	case *messageUpdate:
+		t.Raw = b
+	case *scheduleRequest:
+		t.Raw = b
+	case *EphemeralMessage:
+		t.Raw = b
+	case *CommandResponse:
+		t.Raw = b
+	}
+	return nil
+}
+
+// AddMessage adds the message to the history of the conversation, as if the user posted it.
+// It is not one of the posted messages. If the timestamp is empty, a new one is assigned.
+// AddMessage returns the timestamp of the message.
+func (s *Server) AddMessage(m PostedMessage) string {
+	defer s.mux.Unlock()
+	s.mux.Lock()
+	if m.TS == "" {
+		m.TS = s.timestamp()
+	}
+	s.history = append(s.history, m)
+	return m.TS
+}
+
+// PostedMessages returns the messages posted by the client.
+func (s *Server) PostedMessages() []PostedMessage {
+	defer s.mux.Unlock()
+	s.mux.Lock()
+	return append([]PostedMessage{}, s.messages...)
+}
+
+// WaitPostedMessages waits until the client posts n messages in total and returns them.
+func (s *Server) WaitPostedMessages(ctx context.Context, n int) ([]PostedMessage, error) {
+	if err := s.wait(ctx, func() bool { return len(s.messages) >= n }); err != nil {
+		return nil, err
+	}
+	return s.PostedMessages(), nil
+}
+
+// Uploads returns the files uploaded by the client.
+func (s *Server) Uploads() []Upload {
+	defer s.mux.Unlock()
+	s.mux.Lock()
+	return append([]Upload{}, s.uploads...)
+}
+
+// WaitUploads waits until the client uploads n files in total and returns them.
+func (s *Server) WaitUploads(ctx context.Context, n int) ([]Upload, error) {
+	if err := s.wait(ctx, func() bool { return len(s.uploads) >= n }); err != nil {
+		return nil, err
+	}
+	return s.Uploads(), nil
+}
+
+// Views returns the views opened, pushed or updated by the client.
+func (s *Server) Views() []View {
+	defer s.mux.Unlock()
+	s.mux.Lock()
+	return append([]View{}, s.views...)
+}
+
+// WaitViews waits until the client calls the views API n times in total and returns the views.
+func (s *Server) WaitViews(ctx context.Context, n int) ([]View, error) {
+	if err := s.wait(ctx, func() bool { return len(s.views) >= n }); err != nil {
+		return nil, err
+	}
+	return s.Views(), nil
+}
+
+// CommandResponses returns the responses to the slash commands posted by the client.
+func (s *Server) CommandResponses() []CommandResponse {
+	defer s.mux.Unlock()
+	s.mux.Lock()
+	return append([]CommandResponse{}, s.commandResponses...)
+}
+
+// WaitCommandResponses waits until the client responds to slash commands n times in total and returns the responses.
+func (s *Server) WaitCommandResponses(ctx context.Context, n int) ([]CommandResponse, error) {
+	if err := s.wait(ctx, func() bool { return len(s.commandResponses) >= n }); err != nil {
+		return nil, err
+	}
+	return s.CommandResponses(), nil
+}
+
+// paginate returns the range of the page of n items by the limit and the cursor of the request,
+// and the cursor of the next page.
+func paginate(r *http.Request, n int) (start, end int, next string, ok bool) {
+	limit, _ := strconv.Atoi(r.FormValue("limit"))
+	if limit <= 0 || limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+	if c := r.FormValue("cursor"); c != "" {
+		b, err := base64.StdEncoding.DecodeString(c)
+		if err != nil || !strings.HasPrefix(string(b), cursorPrefix) {
+			return 0, 0, "", false
+		}
+		start, err = strconv.Atoi(strings.TrimPrefix(string(b), cursorPrefix))
+		if err != nil || start < 0 || start > n {
+			return 0, 0, "", false
+		}
+	}
+	end = start + limit
+	if end >= n {
+		return start, n, "", true
+	}
+	return start, end, base64.StdEncoding.EncodeToString([]byte(cursorPrefix + strconv.Itoa(end))), true
+}
@@ -0,0 +1,134 @@
+package socketmode
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultAckDeadline is the time from receiving the envelope to acknowledging it without a payload,
+// unless the handler acknowledges it.
+// Slack expects the acknowledgement within 3 seconds.
+const DefaultAckDeadline = 2 * time.Second
+
+var (
+	// ErrAcknowledged is returned when acknowledging the envelope which has been already acknowledged.
+	ErrAcknowledged = errors.New("envelope already acknowledged")
+
+	// ErrResponsePayloadNotAccepted is returned when acknowledging the envelope
+	// that does not accept a response payload with a payload.
+	ErrResponsePayloadNotAccepted = errors.New("envelope does not accept response payload")
+)
+
+// ViewResponse is the response payload to the view_submission event.
+// see. https://api.slack.com/surfaces/modals#updating_response
+type ViewResponse struct {
+	ResponseAction string            `json:"response_action"`
+	Errors         map[string]string `json:"errors,omitempty"`
+	View           interface{}       `json:"view,omitempty"`
+}
+
+// acknowledger acknowledges an envelope exactly once.
+type acknowledger struct {
+	once           sync.Once
+	conn           *connection
+	envelopeID     string
+	acceptsPayload bool
+	deadline       *time.Timer
+	done           int32 // set to 1 atomically once the envelope is acknowledged or dropped
+}
+
+// newAcknowledger creates the acknowledger of the envelope received from the connection,
+// which acknowledges the envelope without a payload when the deadline expires.
+func (c *Client) newAcknowledger(conn *connection, el *Envelope) *acknowledger {
+	a := &acknowledger{
+		conn:           conn,
+		envelopeID:     el.EnvelopeID,
+		acceptsPayload: el.AcceptsResponsePayload,
+	}
+	envelopeType := el.Type
+	a.deadline = time.AfterFunc(c.ackDeadline, func() {
+		if err := a.send(nil); err == nil {
+			log.Printf("acknowledged at the deadline: envelope_type: %s", envelopeType)
+		}
+	})
+	return a
+}
+
+func (a *acknowledger) ack(payload interface{}) error {
+	if payload != nil && !a.acceptsPayload {
+		return ErrResponsePayloadNotAccepted
+	}
+	a.stop()
+	return a.send(payload)
+}
+
+// send sends the acknowledgement unless it has been sent.
+// It does not touch the deadline, so that the deadline can call it before newAcknowledger returns.
+func (a *acknowledger) send(payload interface{}) error {
+	err := ErrAcknowledged
+	a.once.Do(func() {
+		atomic.StoreInt32(&a.done, 1)
+		err = a.conn.send(Acknowledge{EnvelopeID: a.envelopeID, Payload: payload})
+	})
+	return err
+}
+
+// drop stops the deadline and gives up acknowledging the envelope, so that Slack redelivers it.
+// It returns false if the envelope has been already acknowledged.
+func (a *acknowledger) drop() bool {
+	a.stop()
+	dropped := false
+	a.once.Do(func() {
+		atomic.StoreInt32(&a.done, 1)
+		dropped = true
+	})
+	return dropped
+}
+
+// acknowledged returns true if the envelope has been acknowledged or dropped.
+func (a *acknowledger) acknowledged() bool {
+	return atomic.LoadInt32(&a.done) == 1
+}
+
+// stop stops the deadline of the acknowledger, if any.
+func (a *acknowledger) stop() {
+	if a != nil && a.deadline != nil {
+		a.deadline.Stop()
+	}
+}
+
+// Ack acknowledges the envelope of the event with the response payload, e.g. the message of
+// the slash command response or the ViewResponse of the view submission.
+// If the handler does not acknowledge the event, the envelope is acknowledged
+// without a payload after the handler returns or when the deadline expires.
+// see. https://api.slack.com/apis/connections/socket-implement#acknowledge
+func (e *Event) Ack(payload interface{}) error {
+	if e.ack == nil {
+		return ErrAcknowledged
+	}
+	return e.ack.ack(payload)
+}
+
+// AcceptsResponsePayload returns true, if the event can be acknowledged with a response payload.
+// It returns false once the envelope has been acknowledged, e.g. at the deadline
+// while the event was waiting behind a slow handler.
+func (e *Event) AcceptsResponsePayload() bool {
+	return e.ack != nil && e.ack.acceptsPayload && !e.ack.acknowledged()
+}
+
+// handle passes the event to the handler and acknowledges the envelope unless the handler does.
+// The deadline of the acknowledgement has been started when the envelope was received.
+func (c *Client) handle(ctx context.Context, e *Event, handler func(context.Context, *Event) error) error {
+	if e.ack == nil {
+		return handler(ctx, e)
+	}
+	err := handler(ctx, e)
+	if ackErr := e.ack.ack(nil); ackErr != nil && !errors.Is(ackErr, ErrAcknowledged) {
+		log.Printf("acknowledge error: %v", ackErr)
+	}
+	return err
+}
@@ -0,0 +1,201 @@
+package socketmode_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ikawaha/slackbot/slacktest"
+	"github.com/ikawaha/slackbot/socketmode"
+)
+
+func TestClient_AckWithResponsePayload(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	c := newClient(t, srv)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	receive(ctx, c, func(_ context.Context, e *socketmode.Event) error {
+		if !e.IsSlashCommand() || !e.AcceptsResponsePayload() {
+			return errors.New("unexpected event")
+		}
+		if err := e.Ack(map[string]string{"text": "pong " + e.Text}); err != nil {
+			return err
+		}
+		if e.AcceptsResponsePayload() {
+			t.Error("want no response payload accepted after the ack")
+		}
+		if err := e.Ack(nil); !errors.Is(err, socketmode.ErrAcknowledged) {
+			t.Errorf("want ErrAcknowledged, got %v", err)
+		}
+		return nil
+	})
+
+	id, err := srv.SendSlashCommand(slacktest.SlashCommand{Command: "/ping", Text: "x", UserID: "U1", ChannelID: "C1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ack, err := srv.WaitAck(waitContext(t), id)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got map[string]string
+	if err := json.Unmarshal(ack.Payload, &got); err != nil {
+		t.Fatalf("unexpected error: %v, payload: %s", err, ack.Payload)
+	}
+	if got["text"] != "pong x" {
+		t.Errorf("want pong x, got %+v", got)
+	}
+	if n := len(srv.Acks()); n != 1 {
+		t.Errorf("want 1 ack, got %d", n)
+	}
+}
+
+func TestClient_AckPayloadNotAccepted(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	c := newClient(t, srv)
+	errs := make(chan error, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	receive(ctx, c, func(_ context.Context, e *socketmode.Event) error {
+		errs <- e.Ack(map[string]string{"text": "x"})
+		return nil
+	})
+
+	id, err := srv.SendEvent(socketmode.Event{Type: "message", Text: "hello"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	select {
+	case err := <-errs:
+		if !errors.Is(err, socketmode.ErrResponsePayloadNotAccepted) {
+			t.Errorf("want ErrResponsePayloadNotAccepted, got %v", err)
+		}
+	case <-waitContext(t).Done():
+		t.Fatal("event not received")
+	}
+	// the envelope is acknowledged without the payload after the handler returns.
+	ack, err := srv.WaitAck(waitContext(t), id)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ack.Payload) != 0 {
+		t.Errorf("want ack without payload, got %s", ack.Payload)
+	}
+}
+
+func TestClient_AckDeadline(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	c := newClient(t, srv, socketmode.AckDeadline(50*time.Millisecond))
+	release := make(chan struct{})
+	acks := make(chan error, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	receive(ctx, c, func(_ context.Context, e *socketmode.Event) error {
+		if e.IsMessage() {
+			<-release
+			return nil
+		}
+		if e.AcceptsResponsePayload() {
+			t.Error("want no response payload accepted after the deadline")
+		}
+		acks <- e.Ack(map[string]string{"text": "late"})
+		return nil
+	})
+
+	// the slash command waits behind the slow handler of the message,
+	// and both are acknowledged at the deadline.
+	first, err := srv.SendEvent(socketmode.Event{Type: "message", Channel: "C1", Text: "slow"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := srv.SendSlashCommand(slacktest.SlashCommand{Command: "/ping"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, id := range []string{first, second} {
+		ack, err := srv.WaitAck(waitContext(t), id)
+		if err != nil {
+			t.Fatalf("envelope %s not acknowledged: %v", id, err)
+		}
+		if len(ack.Payload) != 0 {
+			t.Errorf("want ack without payload, got %s", ack.Payload)
+		}
+	}
+	close(release)
+	select {
+	case err := <-acks:
+		if !errors.Is(err, socketmode.ErrAcknowledged) {
+			t.Errorf("want ErrAcknowledged, got %v", err)
+		}
+	case <-waitContext(t).Done():
+		t.Fatal("slash command not received")
+	}
+}
+
+// slowOpen delays apps.connections.open while it is enabled.
+type slowOpen struct {
+	enabled int32
+	delay   time.Duration
+}
+
+func (s *slowOpen) RoundTrip(r *http.Request) (*http.Response, error) {
+	if atomic.LoadInt32(&s.enabled) == 1 && strings.HasSuffix(r.URL.Path, "apps.connections.open") {
+		time.Sleep(s.delay)
+	}
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestClient_AckDeadline_Refresh(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	rt := &slowOpen{delay: 300 * time.Millisecond}
+	c := newClient(t, srv, socketmode.AckDeadline(50*time.Millisecond), socketmode.Transport(rt))
+	release := make(chan struct{})
+	events := make(chan *socketmode.Event, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	receive(ctx, c, func(_ context.Context, e *socketmode.Event) error {
+		if e.Text == "slow" {
+			<-release
+		}
+		events <- e
+		return nil
+	})
+
+	if _, err := srv.SendEvent(socketmode.Event{Type: "message", Text: "slow"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := srv.SendDisconnect(socketmode.DisconnectRefreshRequested); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// the envelope is read while the connection is being refreshed,
+	// and acknowledged at the deadline before the connection is closed.
+	id, err := srv.SendEvent(socketmode.Event{Type: "message", Text: "pending"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	atomic.StoreInt32(&rt.enabled, 1)
+	close(release)
+
+	if e := nextEvent(t, events); e.Text != "slow" {
+		t.Fatalf("want slow, got %s", e.Text)
+	}
+	if _, err := srv.WaitAck(waitContext(t), id); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Slack does not redeliver the acknowledged envelope, so it must not be dropped.
+	if e := nextEvent(t, events); e.Text != "pending" {
+		t.Errorf("want pending, got %s", e.Text)
+	}
+	if err := srv.WaitConnects(waitContext(t), 2, 1); err != nil {
+		t.Errorf("want the connection refreshed, got %d connects and %d open connections", srv.Connects(), srv.NumConnections())
+	}
+}
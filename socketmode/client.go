@@ -3,6 +3,7 @@ package socketmode
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -11,153 +12,297 @@ import (
 	"time"
 
 	"golang.org/x/net/websocket"
+
+	"github.com/ikawaha/slackbot/internal/slackapi"
 )
 
 const (
 	// DefaultTimeout represents the time to wait for a response from slack.
 	DefaultTimeout = 10 * time.Second
+
+	// MaxConnections is the maximum number of connections Slack allows an app to open at once.
+	MaxConnections = 10
 )
 
 const (
-	appsConnectionsOpenEndpoint = `https://slack.com/api/apps.connections.open`
+	// DefaultBaseURL is the base URL of the Slack Web API.
+	DefaultBaseURL = "https://slack.com/api/"
+
+	appsConnectionsOpenMethod = "apps.connections.open"
 )
 
+// ErrClosed is returned when receiving a message from the closed client.
+var ErrClosed = errors.New("client closed")
+
 // Client represents a Slack client.
 type Client struct {
-	mux     sync.Mutex
-	socket  *websocket.Conn
-	token   string
-	timeout time.Duration
-	debug   bool
+	mux        sync.Mutex
+	conns      []*connection
+	size       int
+	incoming   chan received
+	readers    sync.WaitGroup
+	closed     chan struct{}
+	token      string
+	baseURL    string
+	httpclient *http.Client
+	timeout    time.Duration
+	debug      bool
+
+	ackDeadline     time.Duration
+	reconnectPolicy ReconnectPolicy
+	onReconnect     ReconnectHook
+	onDisconnect    DisconnectHook
+}
+
+// connection is a WebSocket connection read by its own reader goroutine.
+type connection struct {
+	mux       sync.Mutex // guards writes to the socket
+	socket    *websocket.Conn
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// received is an envelope or an error read from a connection.
+type received struct {
+	conn     *connection
+	envelope *Envelope
+	ack      *acknowledger
+	err      error
 }
 
 // New creates a slack bot with an app-level token.
 func New(token string, opts ...Option) (*Client, error) {
 	ret := Client{
-		token:   token,
+		incoming: make(chan received),
+		closed:   make(chan struct{}),
+		token:    token,
+		baseURL:  DefaultBaseURL,
+		httpclient: &http.Client{
+			Timeout: 5 * time.Second,
+		},
+		size:    1,
 		timeout: DefaultTimeout,
-	}
-	wss, err := connectionOpen(context.TODO(), token)
-	if err != nil {
-		return nil, fmt.Errorf("api connection error, %w", err)
-	}
-	if err := ret.dial(wss); err != nil {
-		return nil, err
+
+		ackDeadline:     DefaultAckDeadline,
+		reconnectPolicy: DefaultReconnectPolicy,
 	}
 	for _, opt := range opts {
 		if err := opt(&ret); err != nil {
 			return nil, err
 		}
 	}
+	for i := 0; i < ret.size; i++ {
+		if err := ret.connect(context.TODO()); err != nil {
+			_ = ret.Close()
+			return nil, err
+		}
+	}
 	return &ret, nil
 }
 
-// Close closes the client.
+// Close closes the client and waits for the readers of the connections to stop.
 func (c *Client) Close() error {
-	return c.socket.Close()
+	c.mux.Lock()
+	select {
+	case <-c.closed:
+	default:
+		close(c.closed)
+	}
+	conns := c.conns
+	c.conns = nil
+	c.mux.Unlock()
+	var err error
+	for _, conn := range conns {
+		if e := conn.close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	c.readers.Wait()
+	return err
+}
+
+// remove closes the connection and removes it from the client.
+func (c *Client) remove(conn *connection) {
+	c.mux.Lock()
+	for i, v := range c.conns {
+		if v == conn {
+			c.conns = append(c.conns[:i], c.conns[i+1:]...)
+			break
+		}
+	}
+	c.mux.Unlock()
+	// the connection is no longer used, so the close error does not matter to the caller.
+	if err := conn.close(); err != nil {
+		log.Printf("connection close error: %v", err)
+	}
+}
+
+func (c *connection) send(v interface{}) error {
+	defer c.mux.Unlock()
+	c.mux.Lock()
+	return websocket.JSON.Send(c.socket, v)
+}
+
+func (c *connection) close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.done)
+		err = c.socket.Close()
+	})
+	return err
+}
+
+func (c *connection) isClosed() bool {
+	select {
+	case <-c.done:
+		return true
+	default:
+		return false
+	}
 }
 
 type socketOpenResponse struct {
-	OK    bool   `json:"ok"`
-	Error string `json:"error"`
-	URL   string `json:"url"`
+	OK  bool   `json:"ok"`
+	URL string `json:"url"`
 }
 
-func connectionOpen(ctx context.Context, token string) (string, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, appsConnectionsOpenEndpoint, nil)
+func (c *Client) connectionOpen(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+appsConnectionsOpenMethod, nil)
 	if err != nil {
 		return "", err
 	}
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Authorization", "Bearer "+c.token)
 
-	client := http.Client{
-		Timeout: 5 * time.Second,
-	}
-	resp, err := client.Do(req)
+	resp, err := c.httpclient.Do(req)
 	if err != nil {
 		return "", fmt.Errorf("websocket requsest access failed: %w", err)
 	}
 	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("websocket request failed: %d (%s)", resp.StatusCode, http.StatusText(resp.StatusCode))
-	}
 	b, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return "", fmt.Errorf("websocket response error: %w", err)
 	}
+	if _, err := slackapi.DecodeResponse(appsConnectionsOpenMethod, resp.StatusCode, b); err != nil {
+		return "", err
+	}
 	var r socketOpenResponse
 	if err := json.Unmarshal(b, &r); err != nil {
 		return "", fmt.Errorf("websocket response decode error: %w", err)
 	}
-	if !r.OK {
-		return "", fmt.Errorf("websocket open error: %s", r.Error)
-	}
 	return r.URL, nil
 }
 
+// dial opens the WebSocket connection to the URL directly, without the HTTP client of the options.
 func (c *Client) dial(url string) error {
 	ws, err := websocket.Dial(url, "", "https://api.slack.com/")
 	if err != nil {
 		return fmt.Errorf("dial error: %w", err)
 	}
+	conn := &connection{
+		socket: ws,
+		done:   make(chan struct{}),
+	}
 	defer c.mux.Unlock()
 	c.mux.Lock()
-	c.socket = ws
+	select {
+	case <-c.closed:
+		_ = ws.Close()
+		return ErrClosed
+	default:
+	}
+	c.conns = append(c.conns, conn)
+	c.readers.Add(1)
+	go c.read(conn)
 	return nil
 }
 
-func (c *Client) reconnect(ctx context.Context) error {
-	_ = c.Close()
-	wss, err := connectionOpen(ctx, c.token)
+// read decodes envelopes from the connection and feeds them to the client
+// until the connection is closed or fails.
+func (c *Client) read(conn *connection) {
+	defer c.readers.Done()
+	for {
+		var e Envelope
+		r := received{conn: conn, envelope: &e}
+		if err := websocket.JSON.Receive(conn.socket, &e); err != nil {
+			if conn.isClosed() {
+				return
+			}
+			r = received{conn: conn, err: fmt.Errorf("receive error: %w", err)}
+		} else if e.EnvelopeID != "" {
+			// the deadline starts now, not when the handler gets the envelope,
+			// so that the envelopes waiting behind a slow handler are acknowledged in time.
+			r.ack = c.newAcknowledger(conn, &e)
+		}
+		select {
+		case c.incoming <- r:
+		case <-conn.done:
+			// the connection has been replaced while the envelope was pending.
+			// Slack redelivers the envelope unless it has been acknowledged at the deadline.
+			if r.ack == nil || r.ack.drop() {
+				return
+			}
+			select {
+			case c.incoming <- r:
+			case <-c.closed:
+			}
+			return
+		}
+		if r.err != nil {
+			return
+		}
+	}
+}
+
+func (c *Client) connect(ctx context.Context) error {
+	wss, err := c.connectionOpen(ctx)
 	if err != nil {
-		return err
+		return fmt.Errorf("api connection error, %w", err)
 	}
 	return c.dial(wss)
 }
 
 // ReceiveMessage receives a message and passes it to a handler for processing.
 func (c *Client) ReceiveMessage(ctx context.Context, handler func(context.Context, *Event) error) error {
-	ch := make(chan interface{}, 1)
-	go func() {
-		var e Envelope
-		if err := websocket.JSON.Receive(c.socket, &e); err != nil {
-			ch <- fmt.Errorf("receive error: %w", err)
-		}
-		ch <- &e
-	}()
 	select {
-	case msg := <-ch:
-		event, err := c.openEnvelope(ctx, msg)
+	case r := <-c.incoming:
+		if r.conn.isClosed() && (r.ack == nil || r.ack.drop()) {
+			// the connection has been replaced while the envelope was pending.
+			// Slack redelivers the envelope unless it has been acknowledged at the deadline.
+			return nil
+		}
+		event, err := c.openEnvelope(ctx, r)
+		var de *DisconnectError
+		if errors.As(err, &de) {
+			return err
+		}
 		if err != nil {
 			log.Println(err, ", reconnect...")
-			if err := c.reconnect(ctx); err != nil {
+			if err := c.reconnect(ctx, r.conn); err != nil {
 				return err
 			}
 		}
 		if event != nil {
-			if err := handler(ctx, event); err != nil {
+			if err := c.handle(ctx, event, handler); err != nil {
 				return err
 			}
 		}
+	case <-c.closed:
+		return ErrClosed
 	case <-ctx.Done():
-		return fmt.Errorf("context done")
+		return fmt.Errorf("context done: %w", ctx.Err())
 	}
 	return nil
 }
 
-func (c *Client) openEnvelope(ctx context.Context, msg interface{}) (*Event, error) {
-	switch t := msg.(type) {
-	case error:
-		return nil, t
-	case *Envelope:
-		return c.processEnvelope(ctx, t)
-	default:
-		return nil, fmt.Errorf("unknown message type: %T, %+v", msg, msg)
+func (c *Client) openEnvelope(ctx context.Context, r received) (*Event, error) {
+	if r.err != nil {
+		return nil, r.err
 	}
+	return c.processEnvelope(ctx, r.conn, r.envelope, r.ack)
 }
 
-func (c *Client) processEnvelope(ctx context.Context, el *Envelope) (*Event, error) {
+func (c *Client) processEnvelope(ctx context.Context, conn *connection, el *Envelope, ack *acknowledger) (*Event, error) {
 	if c.debug {
 		dump, err := json.MarshalIndent(el, "", "  ")
 		if err != nil {
@@ -165,26 +310,57 @@ func (c *Client) processEnvelope(ctx context.Context, el *Envelope) (*Event, err
 		}
 		log.Printf("envelope:%s", dump)
 	}
-	// ack
-	if el.EnvelopeID != "" {
-		if err := websocket.JSON.Send(c.socket, Acknowledge{EnvelopeID: el.EnvelopeID}); err != nil {
-			return nil, fmt.Errorf("acknowledge error: %w", err)
-		}
-	}
+	var (
+		event *Event
+		err   error
+	)
 	switch EnvelopeType(el.Type) {
-	case EventsAPI:
-		return extractEvent(el)
-	case SlashCommands:
-		return newSlashCommandEvent(el)
+	case EventsAPI, SlashCommands, Interactive:
+		event = decodeEvent(el)
 	case Disconnect:
-		log.Printf("refresh: event_type: %s, %#+v", el.Type, el.Payload)
-		return nil, c.reconnect(ctx)
+		err = c.disconnect(ctx, conn, el)
 	case Hello:
 		log.Println("event_type: hello, client has successfully connected to the server")
 	default:
 		log.Printf("skip: event_type: %s, payload: %#+v", el.Type, el.Payload)
 	}
-	return nil, nil
+	if el.EnvelopeID == "" {
+		return event, err
+	}
+	if ack == nil {
+		ack = c.newAcknowledger(conn, el)
+	}
+	if event != nil && err == nil {
+		// the envelope will be acknowledged by the handler or after the handler returns.
+		event.ack = ack
+		return event, nil
+	}
+	if ackErr := ack.ack(nil); ackErr != nil {
+		return nil, fmt.Errorf("acknowledge error: %w", ackErr)
+	}
+	return event, err
+}
+
+// decodeEvent decodes the event of the envelope.
+// The envelope which cannot be decoded is logged and skipped, since the connection is still healthy.
+func decodeEvent(el *Envelope) *Event {
+	var (
+		event *Event
+		err   error
+	)
+	switch EnvelopeType(el.Type) {
+	case EventsAPI:
+		event, err = extractEvent(el)
+	case SlashCommands:
+		event, err = newSlashCommandEvent(el)
+	case Interactive:
+		event, err = newInteractiveEvent(el)
+	}
+	if err != nil {
+		log.Printf("skip: envelope decode error: envelope_type: %s, %v, payload: %s", el.Type, err, el.Payload)
+		return nil
+	}
+	return event
 }
 
 func extractEvent(el *Envelope) (*Event, error) {
@@ -0,0 +1,153 @@
+package socketmode_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ikawaha/slackbot/slacktest"
+	"github.com/ikawaha/slackbot/socketmode"
+)
+
+var testReconnectPolicy = socketmode.ReconnectPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   10 * time.Millisecond,
+	MaxDelay:    100 * time.Millisecond,
+}
+
+func newClient(t *testing.T, srv *slacktest.Server, opts ...socketmode.Option) *socketmode.Client {
+	t.Helper()
+	opts = append([]socketmode.Option{
+		socketmode.BaseURL(srv.URL()),
+		socketmode.SetReconnectPolicy(testReconnectPolicy),
+	}, opts...)
+	c, err := socketmode.New("xapp-token", opts...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+// receive calls ReceiveMessage until it returns an error, which is sent to the returned channel.
+func receive(ctx context.Context, c *socketmode.Client, h func(context.Context, *socketmode.Event) error) <-chan error {
+	ret := make(chan error, 1)
+	go func() {
+		for {
+			if err := c.ReceiveMessage(ctx, h); err != nil {
+				ret <- err
+				return
+			}
+		}
+	}()
+	return ret
+}
+
+func waitContext(t *testing.T) context.Context {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+// nextEvent returns the next event sent to the channel, or fails the test.
+func nextEvent(t *testing.T, events <-chan *socketmode.Event) *socketmode.Event {
+	t.Helper()
+	select {
+	case e := <-events:
+		return e
+	case <-waitContext(t).Done():
+		t.Fatal("event not received")
+	}
+	return nil
+}
+
+func TestClient_ReceiveMessage(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	c := newClient(t, srv)
+	events := make(chan *socketmode.Event, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	receive(ctx, c, func(_ context.Context, e *socketmode.Event) error {
+		events <- e
+		return nil
+	})
+
+	id, err := srv.SendEvent(socketmode.Event{Type: "message", Channel: "C1", UserID: "U1", Text: "hello", TS: "1.000001"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e := nextEvent(t, events); !e.IsMessage() || e.Channel != "C1" || e.UserID != "U1" || e.Text != "hello" {
+		t.Errorf("unexpected event: %+v", e)
+	}
+	ack, err := srv.WaitAck(waitContext(t), id)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ack.Payload) != 0 {
+		t.Errorf("want ack without payload, got %s", ack.Payload)
+	}
+}
+
+func TestClient_ReceiveMessage_Closed(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	c := newClient(t, srv)
+	if err := c.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.ReceiveMessage(context.Background(), nil); err != socketmode.ErrClosed {
+		t.Errorf("want ErrClosed, got %v", err)
+	}
+}
+
+func TestClient_Connections(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	c := newClient(t, srv, socketmode.Connections(3))
+	if n := srv.NumConnections(); n != 3 {
+		t.Errorf("want 3 connections, got %d", n)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := srv.WaitConnects(waitContext(t), 3, 0); err != nil {
+		t.Errorf("want the connections closed, got %d", srv.NumConnections())
+	}
+
+	for _, n := range []int{0, socketmode.MaxConnections + 1} {
+		if _, err := socketmode.New("xapp-token", socketmode.BaseURL(srv.URL()), socketmode.Connections(n)); err == nil {
+			t.Errorf("want error for %d connections", n)
+		}
+	}
+}
+
+func TestClient_ReconnectAfterConnectionLoss(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	c := newClient(t, srv, socketmode.Connections(2))
+	if err := srv.WaitConnects(waitContext(t), 2, 2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	events := make(chan *socketmode.Event, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	receive(ctx, c, func(_ context.Context, e *socketmode.Event) error {
+		events <- e
+		return nil
+	})
+
+	srv.CloseConnections()
+	if err := srv.WaitConnects(waitContext(t), 4, 2); err != nil {
+		t.Fatalf("want 4 connects and 2 open connections, got %d and %d", srv.Connects(), srv.NumConnections())
+	}
+	for _, text := range []string{"a", "b"} {
+		if _, err := srv.SendEvent(socketmode.Event{Type: "message", Text: text}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if e := nextEvent(t, events); e.Text != text {
+			t.Errorf("want %s, got %s", text, e.Text)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package socketmode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+)
+
+// ErrLinkDisabled matches the DisconnectError of the link_disabled reason by errors.Is.
+var ErrLinkDisabled = &DisconnectError{Reason: DisconnectLinkDisabled}
+
+// DisconnectError represents the disconnection by Slack that must not be retried,
+// or the refresh of the connection that failed after all the reconnect attempts.
+type DisconnectError struct {
+	Reason    DisconnectReason
+	DebugInfo json.RawMessage
+	// Err is the error of the failed refresh.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *DisconnectError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("disconnected: reason: %s, debug_info: %s, refresh failed: %v", e.Reason, e.DebugInfo, e.Err)
+	}
+	return fmt.Sprintf("disconnected: reason: %s, debug_info: %s", e.Reason, e.DebugInfo)
+}
+
+// Unwrap returns the error of the failed refresh.
+func (e *DisconnectError) Unwrap() error {
+	return e.Err
+}
+
+// Is returns true if the target is a DisconnectError of the same reason.
+func (e *DisconnectError) Is(target error) bool {
+	t, ok := target.(*DisconnectError)
+	return ok && t.Reason == e.Reason
+}
+
+// DisconnectHook is called when the client receives a disconnect envelope.
+type DisconnectHook func(reason DisconnectReason, debugInfo json.RawMessage)
+
+// disconnect handles the disconnect envelope received from the connection.
+// On refresh, the new connection is opened before the old one is closed so that no events are missed.
+// The returned error is nil or the *DisconnectError, so that the caller does not reconnect again.
+func (c *Client) disconnect(ctx context.Context, conn *connection, el *Envelope) error {
+	reason := DisconnectReason(el.Reason)
+	log.Printf("disconnect: reason: %s, debug_info: %s", reason, el.DebugInfo)
+	if c.onDisconnect != nil {
+		c.onDisconnect(reason, el.DebugInfo)
+	}
+	if reason == DisconnectLinkDisabled {
+		c.remove(conn)
+		return &DisconnectError{Reason: reason, DebugInfo: el.DebugInfo}
+	}
+	if err := c.connect(ctx); err != nil {
+		log.Printf("refresh failed: %v", err)
+		if err := c.reconnect(ctx, conn); err != nil {
+			return &DisconnectError{Reason: reason, DebugInfo: el.DebugInfo, Err: err}
+		}
+		return nil
+	}
+	c.remove(conn)
+	return nil
+}
@@ -0,0 +1,134 @@
+package socketmode_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/ikawaha/slackbot/slacktest"
+	"github.com/ikawaha/slackbot/socketmode"
+)
+
+func TestDisconnectError(t *testing.T) {
+	errRefresh := errors.New("refresh error")
+	err := error(&socketmode.DisconnectError{Reason: socketmode.DisconnectRefreshRequested, Err: errRefresh})
+	if !errors.Is(err, errRefresh) {
+		t.Errorf("want the refresh error unwrapped, got %v", err)
+	}
+	if errors.Is(err, socketmode.ErrLinkDisabled) {
+		t.Errorf("want refresh_requested not to match ErrLinkDisabled")
+	}
+	if err := error(&socketmode.DisconnectError{Reason: socketmode.DisconnectLinkDisabled}); !errors.Is(err, socketmode.ErrLinkDisabled) {
+		t.Errorf("want link_disabled to match ErrLinkDisabled")
+	}
+}
+
+func TestClient_Refresh(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	var (
+		mux         sync.Mutex
+		disconnects []socketmode.DisconnectReason
+		reconnects  int
+	)
+	c := newClient(t, srv,
+		socketmode.Connections(2),
+		socketmode.OnDisconnect(func(reason socketmode.DisconnectReason, _ json.RawMessage) {
+			defer mux.Unlock()
+			mux.Lock()
+			disconnects = append(disconnects, reason)
+		}),
+		socketmode.OnReconnect(func(int, error) {
+			defer mux.Unlock()
+			mux.Lock()
+			reconnects++
+		}),
+	)
+	if err := srv.WaitConnects(waitContext(t), 2, 2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	events := make(chan *socketmode.Event, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	receive(ctx, c, func(_ context.Context, e *socketmode.Event) error {
+		events <- e
+		return nil
+	})
+
+	if err := srv.SendDisconnect(socketmode.DisconnectRefreshRequested); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// the new connection replaces the refreshed one.
+	if err := srv.WaitConnects(waitContext(t), 3, 2); err != nil {
+		t.Fatalf("want 3 connects and 2 open connections, got %d and %d", srv.Connects(), srv.NumConnections())
+	}
+	for _, text := range []string{"a", "b"} {
+		if _, err := srv.SendEvent(socketmode.Event{Type: "message", Text: text}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if e := nextEvent(t, events); e.Text != text {
+			t.Errorf("want %s, got %s", text, e.Text)
+		}
+	}
+	if n := srv.Connects(); n != 3 {
+		t.Errorf("want 3 connects, got %d", n)
+	}
+	defer mux.Unlock()
+	mux.Lock()
+	if len(disconnects) != 1 || disconnects[0] != socketmode.DisconnectRefreshRequested {
+		t.Errorf("unexpected disconnects: %v", disconnects)
+	}
+	if reconnects != 0 {
+		t.Errorf("refresh must not reconnect, got %d reconnects", reconnects)
+	}
+}
+
+func TestClient_Refresh_Failed(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	c := newClient(t, srv)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := receive(ctx, c, func(context.Context, *socketmode.Event) error { return nil })
+
+	// neither the refresh nor the reconnects can open a connection.
+	srv.SetError("apps.connections.open", "internal_error")
+	if err := srv.SendDisconnect(socketmode.DisconnectRefreshRequested); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	select {
+	case err := <-done:
+		var de *socketmode.DisconnectError
+		if !errors.As(err, &de) || de.Reason != socketmode.DisconnectRefreshRequested || de.Err == nil {
+			t.Errorf("want DisconnectError of the failed refresh, got %v", err)
+		}
+	case <-waitContext(t).Done():
+		t.Fatal("ReceiveMessage did not stop")
+	}
+}
+
+func TestClient_LinkDisabled(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	c := newClient(t, srv)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := receive(ctx, c, func(context.Context, *socketmode.Event) error { return nil })
+
+	if err := srv.SendDisconnect(socketmode.DisconnectLinkDisabled); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	select {
+	case err := <-done:
+		if !errors.Is(err, socketmode.ErrLinkDisabled) {
+			t.Errorf("want ErrLinkDisabled, got %v", err)
+		}
+	case <-waitContext(t).Done():
+		t.Fatal("ReceiveMessage did not stop on link_disabled")
+	}
+	if n := srv.Connects(); n != 1 {
+		t.Errorf("link_disabled must not reconnect, got %d connects", n)
+	}
+}
@@ -1,7 +1,10 @@
 package socketmode
 
 import (
+	"bytes"
 	"encoding/json"
+
+	"github.com/ikawaha/slackbot/webapi"
 )
 
 // EnvelopeType is the Slack event envelope type.
@@ -24,6 +27,22 @@ const (
 	Hello EnvelopeType = "hello"
 )
 
+// DisconnectReason is the reason of the disconnect envelope.
+// see. https://api.slack.com/apis/connections/socket-implement#disconnect
+type DisconnectReason string
+
+const (
+	// DisconnectWarning is sent a few seconds before the connection is refreshed.
+	DisconnectWarning DisconnectReason = "warning"
+
+	// DisconnectRefreshRequested is sent when the connection is about to be refreshed.
+	DisconnectRefreshRequested DisconnectReason = "refresh_requested"
+
+	// DisconnectLinkDisabled is sent when the socket mode is turned off for the app.
+	// The client must not reconnect.
+	DisconnectLinkDisabled DisconnectReason = "link_disabled"
+)
+
 // Envelope represents the response of the Slack event API.
 type Envelope struct {
 	Type                   string          `json:"type"`
@@ -74,18 +93,82 @@ type Event struct {
 	TS          string `json:"ts"`
 	EventTS     string `json:"event_ts"`
 	ChannelType string `json:"channel_type"`
+	SubType     string `json:"subtype"`
+
+	// for messages in a thread
+	ThreadTS     string `json:"thread_ts"`
+	ParentUserID string `json:"parent_user_id"`
 
 	// extended for slash_command
 	Command     string `json:"command"`
 	UserName    string `json:"user_name"`
 	ResponseURL string `json:"response_url"`
 	TriggerID   string `json:"trigger_id"`
+
+	// extended for interactive
+	Interaction *InteractionPayload `json:"-"`
+
+	// User is the user object of the user_change and team_join events.
+	User *webapi.User `json:"-"`
+
+	// Conversation is the channel object of the channel_created and channel_rename events.
+	Conversation *webapi.Channel `json:"-"`
+
+	// Raw is the JSON of the event as it is, to decode the fields specific to the event type.
+	Raw json.RawMessage `json:"-"`
+
+	ack *acknowledger
+}
+
+// UnmarshalJSON decodes the event. The user and the channel of the event are the IDs or
+// the objects depending on the event type, e.g. the user of the user_change event is the user object.
+func (e *Event) UnmarshalJSON(b []byte) error {
+	type event Event
+	v := struct {
+		*event
+		User    json.RawMessage `json:"user"`
+		Channel json.RawMessage `json:"channel"`
+	}{
+		event: (*event)(e),
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	switch {
+	case bytes.HasPrefix(v.User, []byte("{")):
+		var u webapi.User
+		if err := json.Unmarshal(v.User, &u); err != nil {
+			return err
+		}
+		e.User = &u
+		e.UserID = u.ID
+	case len(v.User) > 0:
+		if err := json.Unmarshal(v.User, &e.UserID); err != nil {
+			return err
+		}
+	}
+	switch {
+	case bytes.HasPrefix(v.Channel, []byte("{")):
+		var ch webapi.Channel
+		if err := json.Unmarshal(v.Channel, &ch); err != nil {
+			return err
+		}
+		e.Conversation = &ch
+		e.Channel = ch.ID
+	case len(v.Channel) > 0:
+		if err := json.Unmarshal(v.Channel, &e.Channel); err != nil {
+			return err
+		}
+	}
+	e.Raw = append(json.RawMessage(nil), b...)
+	return nil
 }
 
 // Acknowledge represents the payload type of the response back to Slack acknowledging.
 // see. https://api.slack.com/apis/connections/socket-implement#acknowledge
 type Acknowledge struct {
-	EnvelopeID string `json:"envelope_id"`
+	EnvelopeID string      `json:"envelope_id"`
+	Payload    interface{} `json:"payload,omitempty"`
 }
 
 // EventType is the Slack event type.
@@ -102,6 +185,34 @@ const (
 
 	// SlashCommand is a slash command.
 	SlashCommand = "slash_command"
+
+	// UserChange is a Slack event type.
+	// A member's data has changed.
+	UserChange EventType = "user_change"
+
+	// TeamJoin is a Slack event type.
+	// A new member has joined.
+	TeamJoin EventType = "team_join"
+
+	// ChannelCreated is a Slack event type.
+	// A channel was created.
+	ChannelCreated EventType = "channel_created"
+
+	// ChannelRename is a Slack event type.
+	// A channel was renamed.
+	ChannelRename EventType = "channel_rename"
+
+	// ChannelArchive is a Slack event type.
+	// A channel was archived.
+	ChannelArchive EventType = "channel_archive"
+
+	// ChannelUnarchive is a Slack event type.
+	// A channel was unarchived.
+	ChannelUnarchive EventType = "channel_unarchive"
+
+	// ChannelDeleted is a Slack event type.
+	// A channel was deleted.
+	ChannelDeleted EventType = "channel_deleted"
 )
 
 // Is returns true, if the event type equals tne given event type.
@@ -123,3 +234,23 @@ func (e Event) IsMessage() bool {
 func (e Event) IsSlashCommand() bool {
 	return e.Is(SlashCommand)
 }
+
+// InThread returns true, if the event is a message in a thread.
+func (e Event) InThread() bool {
+	return e.ThreadTS != ""
+}
+
+// ReplyThreadTS returns the timestamp of the thread to reply to the event in.
+// It is the parent message of the thread if the message is in a thread, otherwise the message itself.
+func (e Event) ReplyThreadTS() string {
+	if e.ThreadTS != "" {
+		return e.ThreadTS
+	}
+	if e.TS != "" {
+		return e.TS
+	}
+	if e.Interaction != nil {
+		return e.Interaction.Container.MessageTS
+	}
+	return ""
+}
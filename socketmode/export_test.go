@@ -0,0 +1,8 @@
+package socketmode
+
+import "time"
+
+// ReconnectDelay exports the delay of the reconnect policy for testing.
+func ReconnectDelay(p ReconnectPolicy, n int) time.Duration {
+	return p.delay(n)
+}
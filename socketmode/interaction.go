@@ -0,0 +1,203 @@
+package socketmode
+
+import (
+	"encoding/json"
+)
+
+const (
+	// BlockActions is an interactive event type.
+	// A user clicked a button or chose an item of an interactive component.
+	BlockActions EventType = "block_actions"
+
+	// ViewSubmission is an interactive event type.
+	// A user submitted a modal view.
+	ViewSubmission EventType = "view_submission"
+
+	// ViewClosed is an interactive event type.
+	// A user closed a modal view which notifies on close.
+	ViewClosed EventType = "view_closed"
+
+	// Shortcut is an interactive event type.
+	// A user called a global shortcut.
+	Shortcut EventType = "shortcut"
+
+	// MessageAction is an interactive event type.
+	// A user called a message shortcut.
+	MessageAction EventType = "message_action"
+)
+
+// InteractionPayload represents the payload of the interactive envelope.
+// see. https://api.slack.com/reference/interaction-payloads
+type InteractionPayload struct {
+	Type        string             `json:"type"`
+	Team        InteractionTeam    `json:"team"`
+	User        InteractionUser    `json:"user"`
+	APIAppID    string             `json:"api_app_id"`
+	Token       string             `json:"token"`
+	TriggerID   string             `json:"trigger_id"`
+	ActionTS    string             `json:"action_ts"`
+	CallbackID  string             `json:"callback_id"`
+	ResponseURL string             `json:"response_url"`
+	Container   Container          `json:"container"`
+	Channel     InteractionChannel `json:"channel"`
+	Message     json.RawMessage    `json:"message"`
+	Actions     []Action           `json:"actions"`
+	State       *ViewState         `json:"state"`
+	View        *View              `json:"view"`
+	IsCleared   bool               `json:"is_cleared"` // view_closed
+
+	// for view_submission
+	ResponseURLs []ResponseURL `json:"response_urls"`
+}
+
+// InteractionTeam is the workspace where the interaction happened.
+type InteractionTeam struct {
+	ID     string `json:"id"`
+	Domain string `json:"domain"`
+}
+
+// InteractionUser is the user who interacted.
+type InteractionUser struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	Name     string `json:"name"`
+	TeamID   string `json:"team_id"`
+}
+
+// InteractionChannel is the channel where the interaction happened.
+type InteractionChannel struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Container is the source of the interaction, a message or a view.
+type Container struct {
+	Type        string `json:"type"`
+	MessageTS   string `json:"message_ts"`
+	ChannelID   string `json:"channel_id"`
+	IsEphemeral bool   `json:"is_ephemeral"`
+	ViewID      string `json:"view_id"`
+}
+
+// Text is the text object of the interactive component.
+type Text struct {
+	Type  string `json:"type"`
+	Text  string `json:"text"`
+	Emoji bool   `json:"emoji,omitempty"`
+}
+
+// SelectedOption is the option chosen by a user.
+type SelectedOption struct {
+	Text  Text   `json:"text"`
+	Value string `json:"value"`
+}
+
+// ActionValue is the value of the interactive component.
+type ActionValue struct {
+	Type                  string           `json:"type"`
+	Value                 string           `json:"value,omitempty"`
+	SelectedOption        *SelectedOption  `json:"selected_option,omitempty"`
+	SelectedOptions       []SelectedOption `json:"selected_options,omitempty"`
+	SelectedUser          string           `json:"selected_user,omitempty"`
+	SelectedUsers         []string         `json:"selected_users,omitempty"`
+	SelectedChannel       string           `json:"selected_channel,omitempty"`
+	SelectedChannels      []string         `json:"selected_channels,omitempty"`
+	SelectedConversation  string           `json:"selected_conversation,omitempty"`
+	SelectedConversations []string         `json:"selected_conversations,omitempty"`
+	SelectedDate          string           `json:"selected_date,omitempty"`
+	SelectedTime          string           `json:"selected_time,omitempty"`
+	SelectedDateTime      int64            `json:"selected_date_time,omitempty"`
+}
+
+// Action is the interactive component a user acted on.
+type Action struct {
+	ActionValue
+	ActionID string `json:"action_id"`
+	BlockID  string `json:"block_id"`
+	Text     *Text  `json:"text,omitempty"`
+	ActionTS string `json:"action_ts"`
+}
+
+// ViewState is the values of the input blocks, keyed by block ID and action ID.
+type ViewState struct {
+	Values map[string]map[string]ActionValue `json:"values"`
+}
+
+// Value returns the value of the component identified by the block ID and action ID.
+func (s ViewState) Value(blockID, actionID string) (ActionValue, bool) {
+	v, ok := s.Values[blockID][actionID]
+	return v, ok
+}
+
+// View represents the modal or the home tab view.
+type View struct {
+	ID              string          `json:"id"`
+	TeamID          string          `json:"team_id"`
+	Type            string          `json:"type"`
+	CallbackID      string          `json:"callback_id"`
+	PrivateMetadata string          `json:"private_metadata"`
+	ExternalID      string          `json:"external_id"`
+	Hash            string          `json:"hash"`
+	Title           json.RawMessage `json:"title"`
+	Blocks          json.RawMessage `json:"blocks"`
+	State           ViewState       `json:"state"`
+	RootViewID      string          `json:"root_view_id"`
+	PreviousViewID  string          `json:"previous_view_id"`
+	AppID           string          `json:"app_id"`
+	BotID           string          `json:"bot_id"`
+}
+
+// ResponseURL is the URL to post a message in response to the view submission.
+type ResponseURL struct {
+	BlockID     string `json:"block_id"`
+	ActionID    string `json:"action_id"`
+	ChannelID   string `json:"channel_id"`
+	ResponseURL string `json:"response_url"`
+}
+
+func newInteractiveEvent(el *Envelope) (*Event, error) {
+	var p InteractionPayload
+	if err := json.Unmarshal(el.Payload, &p); err != nil {
+		return nil, err
+	}
+	channelID := p.Channel.ID
+	if channelID == "" {
+		channelID = p.Container.ChannelID
+	}
+	return &Event{
+		Type:        p.Type,
+		Channel:     channelID,
+		UserID:      p.User.ID,
+		AppID:       p.APIAppID,
+		TeamID:      p.Team.ID,
+		UserName:    p.User.Username,
+		ResponseURL: p.ResponseURL,
+		TriggerID:   p.TriggerID,
+		Interaction: &p,
+	}, nil
+}
+
+// IsBlockActions returns true, if the event type is "block_actions".
+func (e Event) IsBlockActions() bool {
+	return e.Is(BlockActions)
+}
+
+// IsViewSubmission returns true, if the event type is "view_submission".
+func (e Event) IsViewSubmission() bool {
+	return e.Is(ViewSubmission)
+}
+
+// IsViewClosed returns true, if the event type is "view_closed".
+func (e Event) IsViewClosed() bool {
+	return e.Is(ViewClosed)
+}
+
+// IsShortcut returns true, if the event type is "shortcut".
+func (e Event) IsShortcut() bool {
+	return e.Is(Shortcut)
+}
+
+// IsMessageAction returns true, if the event type is "message_action".
+func (e Event) IsMessageAction() bool {
+	return e.Is(MessageAction)
+}
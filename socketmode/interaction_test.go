@@ -0,0 +1,137 @@
+package socketmode_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/ikawaha/slackbot/slacktest"
+	"github.com/ikawaha/slackbot/socketmode"
+)
+
+func TestClient_BlockActions(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	c := newClient(t, srv)
+	events := make(chan *socketmode.Event, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	receive(ctx, c, func(_ context.Context, e *socketmode.Event) error {
+		events <- e
+		return nil
+	})
+
+	id, err := srv.SendInteraction(socketmode.InteractionPayload{
+		Type:      "block_actions",
+		Team:      socketmode.InteractionTeam{ID: "T1"},
+		User:      socketmode.InteractionUser{ID: "U1", Username: "alice"},
+		TriggerID: "trigger-1",
+		Container: socketmode.Container{Type: "message", MessageTS: "1.000001", ChannelID: "C1"},
+		Actions: []socketmode.Action{
+			{ActionID: "approve", BlockID: "b1", ActionValue: socketmode.ActionValue{Type: "button", Value: "yes"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	e := nextEvent(t, events)
+	if !e.IsBlockActions() || e.Channel != "C1" || e.UserID != "U1" || e.UserName != "alice" || e.TeamID != "T1" || e.TriggerID != "trigger-1" {
+		t.Errorf("unexpected event: %+v", e)
+	}
+	if e.ChannelType != "" {
+		t.Errorf("want no channel type, got %q", e.ChannelType)
+	}
+	if e.Interaction == nil || len(e.Interaction.Actions) != 1 {
+		t.Fatalf("unexpected interaction: %+v", e.Interaction)
+	}
+	if a := e.Interaction.Actions[0]; a.ActionID != "approve" || a.BlockID != "b1" || a.Value != "yes" {
+		t.Errorf("unexpected action: %+v", a)
+	}
+	if e.AcceptsResponsePayload() {
+		t.Error("want block_actions not to accept a response payload")
+	}
+	if _, err := srv.WaitAck(waitContext(t), id); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestClient_ViewSubmission(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	c := newClient(t, srv)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	receive(ctx, c, func(_ context.Context, e *socketmode.Event) error {
+		if !e.IsViewSubmission() || e.Interaction.View == nil {
+			t.Errorf("unexpected event: %+v", e)
+			return nil
+		}
+		v, ok := e.Interaction.View.State.Value("title", "input")
+		if !ok || v.Value != "" {
+			return e.Ack(socketmode.ViewResponse{ResponseAction: "clear"})
+		}
+		return e.Ack(socketmode.ViewResponse{
+			ResponseAction: "errors",
+			Errors:         map[string]string{"title": "required"},
+		})
+	})
+
+	id, err := srv.SendInteraction(socketmode.InteractionPayload{
+		Type: "view_submission",
+		User: socketmode.InteractionUser{ID: "U1"},
+		View: &socketmode.View{
+			ID:         "V1",
+			CallbackID: "create",
+			State: socketmode.ViewState{Values: map[string]map[string]socketmode.ActionValue{
+				"title": {"input": {Type: "plain_text_input", Value: ""}},
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ack, err := srv.WaitAck(waitContext(t), id)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got socketmode.ViewResponse
+	if err := json.Unmarshal(ack.Payload, &got); err != nil {
+		t.Fatalf("unexpected error: %v, payload: %s", err, ack.Payload)
+	}
+	if got.ResponseAction != "errors" || got.Errors["title"] != "required" {
+		t.Errorf("unexpected view response: %+v", got)
+	}
+}
+
+func TestClient_UndecodableEnvelope(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	c := newClient(t, srv)
+	events := make(chan *socketmode.Event, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	receive(ctx, c, func(_ context.Context, e *socketmode.Event) error {
+		events <- e
+		return nil
+	})
+
+	for _, typ := range []socketmode.EnvelopeType{socketmode.EventsAPI, socketmode.SlashCommands, socketmode.Interactive} {
+		id, err := srv.SendEnvelope(socketmode.Envelope{Type: string(typ), Payload: json.RawMessage(`[1]`)})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := srv.WaitAck(waitContext(t), id); err != nil {
+			t.Errorf("%s: want the envelope acknowledged, got %v", typ, err)
+		}
+	}
+	// the connection is kept, and the following events are delivered.
+	if _, err := srv.SendEvent(socketmode.Event{Type: "message", Text: "next"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e := nextEvent(t, events); e.Text != "next" {
+		t.Errorf("want next, got %s", e.Text)
+	}
+	if n := srv.Connects(); n != 1 {
+		t.Errorf("want no reconnect, got %d connects", n)
+	}
+}
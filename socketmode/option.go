@@ -1,5 +1,13 @@
 package socketmode
 
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ikawaha/slackbot/internal/slackapi"
+)
+
 // Option represents the client's option.
 type Option func(*Client) error
 
@@ -10,3 +18,96 @@ func Debug() Option {
 		return nil
 	}
 }
+
+// SetReconnectPolicy sets the policy of retrying reconnection.
+// The BaseDelay must be positive and the MaxDelay must not be less than the BaseDelay,
+// so that the client does not retry in a tight loop.
+func SetReconnectPolicy(p ReconnectPolicy) Option {
+	return func(c *Client) error {
+		if p.BaseDelay <= 0 || p.MaxDelay < p.BaseDelay {
+			return fmt.Errorf("invalid reconnect delay: %+v", p)
+		}
+		if p.Jitter < 0 || p.Jitter > 1 {
+			return fmt.Errorf("jitter out of range [0, 1]: %v", p.Jitter)
+		}
+		c.reconnectPolicy = p
+		return nil
+	}
+}
+
+// OnReconnect sets the hook called after each reconnect attempt.
+func OnReconnect(h ReconnectHook) Option {
+	return func(c *Client) error {
+		c.onReconnect = h
+		return nil
+	}
+}
+
+// OnDisconnect sets the hook called when the client receives a disconnect envelope.
+func OnDisconnect(h DisconnectHook) Option {
+	return func(c *Client) error {
+		c.onDisconnect = h
+		return nil
+	}
+}
+
+// Connections sets the number of connections to keep open.
+// Slack spreads events across the connections, so the client keeps receiving events
+// on the other connections while one of them is refreshed.
+func Connections(n int) Option {
+	return func(c *Client) error {
+		if n < 1 || n > MaxConnections {
+			return fmt.Errorf("number of connections out of range [1, %d]: %d", MaxConnections, n)
+		}
+		c.size = n
+		return nil
+	}
+}
+
+// AckDeadline sets the time to wait for a handler to acknowledge the envelope, from when the envelope is received.
+// When the deadline expires, the envelope is acknowledged without a payload.
+func AckDeadline(d time.Duration) Option {
+	return func(c *Client) error {
+		if d <= 0 {
+			return fmt.Errorf("ack deadline must be positive: %v", d)
+		}
+		c.ackDeadline = d
+		return nil
+	}
+}
+
+// BaseURL sets the base URL of the Web API to open connections,
+// e.g. the URL of a fake server for testing or of a gateway.
+func BaseURL(u string) Option {
+	return func(c *Client) error {
+		v, err := slackapi.NormalizeBaseURL(u)
+		if err != nil {
+			return err
+		}
+		c.baseURL = v
+		return nil
+	}
+}
+
+// HTTPClient sets the HTTP client to call apps.connections.open, which returns the WebSocket URL.
+// The WebSocket connection itself is dialed directly, not by the HTTP client.
+func HTTPClient(hc *http.Client) Option {
+	return func(c *Client) error {
+		if hc == nil {
+			return fmt.Errorf("http client is nil")
+		}
+		c.httpclient = hc
+		return nil
+	}
+}
+
+// Transport sets the round tripper of the HTTP client to call apps.connections.open.
+// The WebSocket connection itself is dialed directly, so it does not go through a proxy set by the round tripper.
+func Transport(rt http.RoundTripper) Option {
+	return func(c *Client) error {
+		hc := *c.httpclient
+		hc.Transport = rt
+		c.httpclient = &hc
+		return nil
+	}
+}
@@ -0,0 +1,87 @@
+package socketmode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ikawaha/slackbot/internal/slackapi"
+	"github.com/ikawaha/slackbot/webapi"
+)
+
+// ReconnectPolicy represents how the client retries reconnecting to Slack.
+type ReconnectPolicy struct {
+	// MaxAttempts is the maximum number of attempts. Zero or less means no limit.
+	MaxAttempts int
+	// BaseDelay is the delay before the second attempt. It is doubled for each subsequent attempt.
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between attempts.
+	MaxDelay time.Duration
+	// Jitter is the fraction of the delay to be randomized, in the range [0, 1].
+	Jitter float64
+}
+
+// DefaultReconnectPolicy is the reconnect policy used unless another one is specified.
+var DefaultReconnectPolicy = ReconnectPolicy{
+	MaxAttempts: 10,
+	BaseDelay:   time.Second,
+	MaxDelay:    time.Minute,
+	Jitter:      0.5,
+}
+
+// ReconnectHook is called after each reconnect attempt.
+// The err is nil if the attempt succeeded.
+type ReconnectHook func(attempt int, err error)
+
+// delay returns the time to wait before the attempt following the n-th failed attempt.
+func (p ReconnectPolicy) delay(n int) time.Duration {
+	return slackapi.Backoff(n, p.BaseDelay, p.MaxDelay, p.Jitter)
+}
+
+// reconnect replaces the connection with a new one.
+func (c *Client) reconnect(ctx context.Context, conn *connection) error {
+	c.remove(conn)
+	p := c.reconnectPolicy
+	var err error
+	attempt := 1
+	for ; p.MaxAttempts <= 0 || attempt <= p.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			t := time.NewTimer(p.delay(attempt - 1))
+			select {
+			case <-t.C:
+			case <-c.closed:
+				t.Stop()
+				return ErrClosed
+			case <-ctx.Done():
+				t.Stop()
+				return fmt.Errorf("reconnect canceled: %w", ctx.Err())
+			}
+		}
+		err = c.connect(ctx)
+		if c.onReconnect != nil {
+			c.onReconnect(attempt, err)
+		}
+		if err == nil || errors.Is(err, ErrClosed) || !retryable(err) {
+			return err
+		}
+		log.Printf("reconnect failed: attempt: %d, %v", attempt, err)
+	}
+	return fmt.Errorf("reconnect failed after %d attempts: %w", attempt-1, err)
+}
+
+// retryable returns false for the errors that do not resolve by retrying, such as invalid tokens.
+func retryable(err error) bool {
+	for _, v := range []error{
+		webapi.ErrNotAuthed,
+		webapi.ErrInvalidAuth,
+		webapi.ErrAccountInactive,
+		webapi.ErrTokenRevoked,
+	} {
+		if errors.Is(err, v) {
+			return false
+		}
+	}
+	return true
+}
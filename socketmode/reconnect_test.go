@@ -0,0 +1,192 @@
+package socketmode_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ikawaha/slackbot/slacktest"
+	"github.com/ikawaha/slackbot/socketmode"
+	"github.com/ikawaha/slackbot/webapi"
+)
+
+func TestReconnectPolicy_Delay(t *testing.T) {
+	p := socketmode.ReconnectPolicy{BaseDelay: 10 * time.Millisecond, MaxDelay: 80 * time.Millisecond}
+	want := []time.Duration{10, 20, 40, 80, 80, 80}
+	for i, v := range want {
+		n := i + 1
+		if got := socketmode.ReconnectDelay(p, n); got != v*time.Millisecond {
+			t.Errorf("attempt %d: want %v, got %v", n, v*time.Millisecond, got)
+		}
+	}
+
+	p.Jitter = 0.5
+	for i, v := range want {
+		n := i + 1
+		d := v * time.Millisecond
+		if got := socketmode.ReconnectDelay(p, n); got < d/2 || got > d {
+			t.Errorf("attempt %d: want in [%v, %v], got %v", n, d/2, d, got)
+		}
+	}
+}
+
+func TestSetReconnectPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  socketmode.ReconnectPolicy
+		wantErr bool
+	}{
+		{name: "default", policy: socketmode.DefaultReconnectPolicy},
+		{name: "no max attempts", policy: socketmode.ReconnectPolicy{BaseDelay: time.Second, MaxDelay: time.Second}},
+		{name: "zero value", policy: socketmode.ReconnectPolicy{}, wantErr: true},
+		{name: "zero base delay", policy: socketmode.ReconnectPolicy{MaxAttempts: 3, MaxDelay: time.Second}, wantErr: true},
+		{name: "max delay less than base delay", policy: socketmode.ReconnectPolicy{BaseDelay: time.Second, MaxDelay: time.Millisecond}, wantErr: true},
+		{name: "negative jitter", policy: socketmode.ReconnectPolicy{BaseDelay: time.Second, MaxDelay: time.Second, Jitter: -0.1}, wantErr: true},
+		{name: "jitter over 1", policy: socketmode.ReconnectPolicy{BaseDelay: time.Second, MaxDelay: time.Second, Jitter: 1.1}, wantErr: true},
+	}
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := socketmode.New("xapp-token", socketmode.BaseURL(srv.URL()), socketmode.SetReconnectPolicy(tt.policy))
+			if tt.wantErr {
+				if err == nil {
+					_ = c.Close()
+					t.Fatal("want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			_ = c.Close()
+		})
+	}
+}
+
+type reconnectAttempt struct {
+	attempt int
+	err     error
+}
+
+// reconnectRecorder records the reconnect attempts.
+type reconnectRecorder struct {
+	mux      sync.Mutex
+	attempts []reconnectAttempt
+	hook     func(attempt int)
+}
+
+func (r *reconnectRecorder) onReconnect(attempt int, err error) {
+	r.mux.Lock()
+	r.attempts = append(r.attempts, reconnectAttempt{attempt: attempt, err: err})
+	r.mux.Unlock()
+	if r.hook != nil {
+		r.hook(attempt)
+	}
+}
+
+func (r *reconnectRecorder) get() []reconnectAttempt {
+	defer r.mux.Unlock()
+	r.mux.Lock()
+	return append([]reconnectAttempt{}, r.attempts...)
+}
+
+func TestClient_Reconnect(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	rec := &reconnectRecorder{}
+	// the second attempt fails, and the third one succeeds.
+	rec.hook = func(attempt int) {
+		if attempt == 2 {
+			srv.SetError("apps.connections.open", "")
+		}
+	}
+	c := newClient(t, srv, socketmode.OnReconnect(rec.onReconnect))
+	events := make(chan *socketmode.Event, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	receive(ctx, c, func(_ context.Context, e *socketmode.Event) error {
+		events <- e
+		return nil
+	})
+
+	srv.SetError("apps.connections.open", "internal_error")
+	srv.CloseConnections()
+	if err := srv.WaitConnects(waitContext(t), 2, 1); err != nil {
+		t.Fatalf("want 2 connects and 1 open connection, got %d and %d", srv.Connects(), srv.NumConnections())
+	}
+	if _, err := srv.SendEvent(socketmode.Event{Type: "message", Text: "again"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e := nextEvent(t, events); e.Text != "again" {
+		t.Errorf("want again, got %s", e.Text)
+	}
+	got := rec.get()
+	if len(got) != 3 {
+		t.Fatalf("want 3 attempts, got %+v", got)
+	}
+	for i, v := range got {
+		if v.attempt != i+1 {
+			t.Errorf("want attempt %d, got %d", i+1, v.attempt)
+		}
+		if wantErr := i < 2; (v.err != nil) != wantErr || (wantErr && !errors.Is(v.err, webapi.ErrInternalError)) {
+			t.Errorf("attempt %d: unexpected error: %v", v.attempt, v.err)
+		}
+	}
+}
+
+func TestClient_Reconnect_MaxAttempts(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	rec := &reconnectRecorder{}
+	c := newClient(t, srv, socketmode.OnReconnect(rec.onReconnect))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := receive(ctx, c, func(context.Context, *socketmode.Event) error { return nil })
+
+	srv.SetError("apps.connections.open", "internal_error")
+	srv.CloseConnections()
+	select {
+	case err := <-done:
+		if !errors.Is(err, webapi.ErrInternalError) || !strings.Contains(err.Error(), "after 3 attempts") {
+			t.Errorf("unexpected error: %v", err)
+		}
+	case <-waitContext(t).Done():
+		t.Fatal("ReceiveMessage did not stop")
+	}
+	if n := len(rec.get()); n != 3 {
+		t.Errorf("want 3 attempts, got %d", n)
+	}
+}
+
+func TestClient_Reconnect_NotRetryable(t *testing.T) {
+	for _, code := range []string{"invalid_auth", "token_revoked", "not_authed", "account_inactive"} {
+		t.Run(code, func(t *testing.T) {
+			srv := slacktest.NewServer()
+			defer srv.Close()
+			rec := &reconnectRecorder{}
+			c := newClient(t, srv, socketmode.OnReconnect(rec.onReconnect))
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			done := receive(ctx, c, func(context.Context, *socketmode.Event) error { return nil })
+
+			srv.SetError("apps.connections.open", code)
+			srv.CloseConnections()
+			select {
+			case err := <-done:
+				var apiErr *webapi.APIError
+				if !errors.As(err, &apiErr) || apiErr.Code != code {
+					t.Errorf("want %s, got %v", code, err)
+				}
+			case <-waitContext(t).Done():
+				t.Fatal("ReceiveMessage did not stop")
+			}
+			if got := rec.get(); len(got) != 1 || got[0].attempt != 1 || got[0].err == nil {
+				t.Errorf("want 1 failed attempt, got %+v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package webapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ErrCacheMiss is returned when the cache store has no entries of the kind.
+var ErrCacheMiss = errors.New("cache miss")
+
+// Kinds of the cached entries in the cache store.
+const (
+	usersCacheKind    = "users"
+	channelsCacheKind = "channels"
+)
+
+// CacheStore persists the cached entries, e.g. the users, so that the client can warm-start from them.
+// The entries are encoded to JSON. Implement it to keep the cache in a database such as bbolt or SQLite.
+type CacheStore interface {
+	// Load decodes the entries of the kind into v, and returns the time when they were refreshed,
+	// which was given to Save. It returns ErrCacheMiss if the store has no entries of the kind.
+	Load(ctx context.Context, kind string, v interface{}) (time.Time, error)
+
+	// Save stores the entries of the kind with the time when they were refreshed from Slack,
+	// replacing the stored ones. The time is not the time of saving, so that the entries saved
+	// again without a refresh are still refreshed when they get old.
+	Save(ctx context.Context, kind string, v interface{}, refreshedAt time.Time) error
+}
+
+// MemoryStore is the cache store in memory, which is the default cache store of the client.
+// It is safe for concurrent use.
+type MemoryStore struct {
+	mux     sync.RWMutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	RefreshedAt time.Time       `json:"refreshed_at"`
+	Entries     json.RawMessage `json:"entries"`
+}
+
+// NewMemoryStore creates an empty cache store in memory.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries: map[string]cacheEntry{},
+	}
+}
+
+// Load implements the CacheStore interface.
+func (s *MemoryStore) Load(_ context.Context, kind string, v interface{}) (time.Time, error) {
+	s.mux.RLock()
+	e, ok := s.entries[kind]
+	s.mux.RUnlock()
+	if !ok {
+		return time.Time{}, ErrCacheMiss
+	}
+	if err := json.Unmarshal(e.Entries, v); err != nil {
+		return time.Time{}, fmt.Errorf("cache decode error: %s, %w", kind, err)
+	}
+	return e.RefreshedAt, nil
+}
+
+// Save implements the CacheStore interface.
+func (s *MemoryStore) Save(_ context.Context, kind string, v interface{}, refreshedAt time.Time) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("cache encode error: %s, %w", kind, err)
+	}
+	defer s.mux.Unlock()
+	s.mux.Lock()
+	s.entries[kind] = cacheEntry{RefreshedAt: refreshedAt, Entries: b}
+	return nil
+}
+
+// FileStore is the cache store which saves the entries of each kind to a JSON file in the directory.
+// It is safe for concurrent use.
+// The file is a snapshot of all the entries, which is replaced as a whole on each save;
+// the client warm-starts from it and then refreshes all the entries, not only the changed ones.
+type FileStore struct {
+	mux sync.Mutex
+	dir string
+}
+
+// NewFileStore creates the cache store in the directory. The directory is created if it does not exist.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("cache directory error: %w", err)
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+func (s *FileStore) path(kind string) string {
+	return filepath.Join(s.dir, kind+".json")
+}
+
+// Load implements the CacheStore interface.
+func (s *FileStore) Load(_ context.Context, kind string, v interface{}) (time.Time, error) {
+	s.mux.Lock()
+	b, err := os.ReadFile(s.path(kind))
+	s.mux.Unlock()
+	if errors.Is(err, os.ErrNotExist) {
+		return time.Time{}, ErrCacheMiss
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("cache read error: %w", err)
+	}
+	var e cacheEntry
+	if err := json.Unmarshal(b, &e); err != nil {
+		return time.Time{}, fmt.Errorf("cache decode error: %s, %w", kind, err)
+	}
+	if err := json.Unmarshal(e.Entries, v); err != nil {
+		return time.Time{}, fmt.Errorf("cache decode error: %s, %w", kind, err)
+	}
+	return e.RefreshedAt, nil
+}
+
+// Save implements the CacheStore interface.
+// The file is replaced atomically, so that a crash while saving does not corrupt the cache.
+func (s *FileStore) Save(_ context.Context, kind string, v interface{}, refreshedAt time.Time) error {
+	entries, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("cache encode error: %s, %w", kind, err)
+	}
+	b, err := json.Marshal(cacheEntry{RefreshedAt: refreshedAt, Entries: entries})
+	if err != nil {
+		return fmt.Errorf("cache encode error: %s, %w", kind, err)
+	}
+	defer s.mux.Unlock()
+	s.mux.Lock()
+	f, err := os.CreateTemp(s.dir, kind+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("cache write error: %w", err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(b); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("cache write error: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("cache write error: %w", err)
+	}
+	if err := os.Rename(f.Name(), s.path(kind)); err != nil {
+		return fmt.Errorf("cache write error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package webapi_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ikawaha/slackbot/slacktest"
+	"github.com/ikawaha/slackbot/webapi"
+)
+
+func TestCacheStore(t *testing.T) {
+	fs, err := webapi.NewFileStore(filepath.Join(t.TempDir(), "cache"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stores := []struct {
+		name  string
+		store webapi.CacheStore
+	}{
+		{name: "memory", store: webapi.NewMemoryStore()},
+		{name: "file", store: fs},
+	}
+	ctx := context.Background()
+	for _, tt := range stores {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []webapi.User
+			if _, err := tt.store.Load(ctx, "users", &got); !errors.Is(err, webapi.ErrCacheMiss) {
+				t.Errorf("want ErrCacheMiss, got %v", err)
+			}
+			users := []webapi.User{{ID: "U1", Name: "alice", TZ: "Asia/Tokyo"}, {ID: "U2", Name: "bob"}}
+			at := time.Date(2026, 1, 2, 3, 4, 5, 6, time.FixedZone("JST", 9*60*60))
+			if err := tt.store.Save(ctx, "users", users, at); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			refreshedAt, err := tt.store.Load(ctx, "users", &got)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !refreshedAt.Equal(at) {
+				t.Errorf("want the refreshed time %v, got %v", at, refreshedAt)
+			}
+			if !reflect.DeepEqual(got, users) {
+				t.Errorf("want %+v, got %+v", users, got)
+			}
+			// the kinds are stored separately.
+			var channels []webapi.Channel
+			if _, err := tt.store.Load(ctx, "channels", &channels); !errors.Is(err, webapi.ErrCacheMiss) {
+				t.Errorf("want ErrCacheMiss, got %v", err)
+			}
+		})
+	}
+}
+
+func TestFileStore_Save(t *testing.T) {
+	dir := t.TempDir()
+	s, err := webapi.NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := context.Background()
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := s.Save(ctx, "users", []webapi.User{{ID: "U1"}}, at); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.Save(ctx, "users", []webapi.User{{ID: "U1"}, {ID: "U2"}}, at.Add(time.Hour)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// the failed save keeps the saved file.
+	if err := s.Save(ctx, "users", map[string]interface{}{"x": make(chan int)}, at); err == nil {
+		t.Error("want an encode error")
+	}
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(files) != 1 || files[0].Name() != "users.json" {
+		t.Errorf("want only users.json without temporary files, got %v", files)
+	}
+	var got []webapi.User
+	refreshedAt, err := s.Load(ctx, "users", &got)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || !refreshedAt.Equal(at.Add(time.Hour)) {
+		t.Errorf("want the last saved users, got %+v at %v", got, refreshedAt)
+	}
+
+	// the broken file is not a cache miss.
+	if err := os.WriteFile(filepath.Join(dir, "users.json"), []byte("{"), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := s.Load(ctx, "users", &got); err == nil || errors.Is(err, webapi.ErrCacheMiss) {
+		t.Errorf("want a decode error, got %v", err)
+	}
+}
+
+func TestClient_WarmStart(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	srv.AddUser(webapi.User{ID: "U1", Name: "alice"})
+	srv.AddUser(webapi.User{ID: "U2", Name: "bob"})
+	var lists int32
+	release := make(chan struct{})
+	srv.HandleMethod("users.list", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&lists, 1)
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"ok":true,"members":[{"id":"U1","name":"alice"},{"id":"U2","name":"bob"}]}`)
+	})
+
+	store, err := webapi.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	saved := time.Now().Add(-24 * time.Hour)
+	if err := store.Save(context.Background(), "users", []webapi.User{{ID: "U1", Name: "alice"}}, saved); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the client starts with the saved users without waiting for users.list.
+	c := newClient(t, srv, webapi.CacheUsers(), webapi.SetCacheStore(store))
+	defer c.Close()
+	if u, ok := c.UserDirectory().ByName("alice"); !ok || u.ID != "U1" {
+		t.Errorf("want alice from the store, got %+v, %v", u, ok)
+	}
+	if _, ok := c.UserDirectory().ByName("bob"); ok {
+		t.Error("want bob not to be cached before the refresh")
+	}
+
+	// and then refreshes all the users in the background, and saves them.
+	close(release)
+	eventually(t, func() bool { return c.UserDirectory().Len() == 2 })
+	if got := atomic.LoadInt32(&lists); got != 1 {
+		t.Errorf("want a refresh, got %d", got)
+	}
+	eventually(t, func() bool {
+		var us []webapi.User
+		at, err := store.Load(context.Background(), "users", &us)
+		return err == nil && len(us) == 2 && at.After(saved)
+	})
+
+	// without the saved users, the client refreshes them on startup.
+	empty, err := webapi.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c2 := newClient(t, srv, webapi.CacheUsers(), webapi.SetCacheStore(empty))
+	defer c2.Close()
+	if c2.UserDirectory().Len() != 2 || atomic.LoadInt32(&lists) != 2 {
+		t.Errorf("want the users listed on startup, got %d users after %d lists", c2.UserDirectory().Len(), lists)
+	}
+}
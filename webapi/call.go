@@ -0,0 +1,87 @@
+package webapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ikawaha/slackbot/internal/slackapi"
+)
+
+// Call calls the Web API method with the parameters and decodes the response into the result.
+// The params is sent as a form if it is url.Values, otherwise as a JSON body; nil sends no parameters.
+// Note that some methods, mostly the read methods, accept only the form parameters.
+// The result is a pointer to decode the JSON response into, or nil to discard it.
+// The response which is not ok is returned as an APIError. The calls are throttled and retried
+// as the other methods of the client.
+// see. https://api.slack.com/web
+func (c *Client) Call(ctx context.Context, method string, params, result interface{}) error {
+	_, err := c.call(ctx, method, "", params, result)
+	return err
+}
+
+// call calls the method and returns the common part of the response.
+// The key distinguishes the rate limits of the same method, e.g. the channel of chat.postMessage.
+func (c *Client) call(ctx context.Context, method, key string, params, result interface{}) (*slackapi.Response, error) {
+	var (
+		body        io.Reader
+		contentType string
+	)
+	switch t := params.(type) {
+	case nil:
+	case url.Values:
+		body = strings.NewReader(t.Encode())
+		contentType = "application/x-www-form-urlencoded"
+	default:
+		b, err := json.Marshal(t)
+		if err != nil {
+			return nil, fmt.Errorf("request body marshal error: %w", err)
+		}
+		body = bytes.NewReader(b)
+		contentType = "application/json; charset=utf-8"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint(method), body)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	return c.send(req, method, key, result)
+}
+
+// send sends the request of the method with the token and decodes the response into the result.
+func (c *Client) send(req *http.Request, method, key string, result interface{}) (*slackapi.Response, error) {
+	if c.token == "" {
+		return nil, fmt.Errorf("slack token is empty")
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	resp, err := c.do(req, method, key)
+	if err != nil {
+		return nil, fmt.Errorf("slack %s failed: %w", method, err)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("response body read error: %w", err)
+	}
+	if c.debug {
+		log.Printf("%s: status: %s, body: %s", method, resp.Status, b)
+	}
+	r, err := slackapi.DecodeResponse(method, resp.StatusCode, b)
+	if err != nil {
+		return nil, err
+	}
+	if result != nil {
+		if err := json.Unmarshal(b, result); err != nil {
+			return nil, fmt.Errorf("response body unmarshal error: body=%q, %w", string(b), err)
+		}
+	}
+	return r, nil
+}
@@ -0,0 +1,92 @@
+package webapi
+
+import (
+	"context"
+	"errors"
+)
+
+const (
+	chatUpdateMethod        = "chat.update"
+	chatDeleteMethod        = "chat.delete"
+	chatPostEphemeralMethod = "chat.postEphemeral"
+	chatMeMessageMethod     = "chat.meMessage"
+)
+
+// UpdateMessage updates the message of the timestamp, which the bot posted, with the text and the options.
+// The channel is the ID, or the name which starts with "#".
+// The options which chat.update does not support, e.g. ThreadTS, are ignored by Slack.
+// see. https://api.slack.com/methods/chat.update
+func (c *Client) UpdateMessage(ctx context.Context, channel, ts, msg string, opts ...MessageOption) (*MessageResponse, error) {
+	if ts == "" {
+		return nil, errors.New("timestamp of the message to update is empty")
+	}
+	p, err := c.newMessageParams(ctx, channel, msg, opts)
+	if err != nil {
+		return nil, err
+	}
+	p.TS = ts
+	var ret MessageResponse
+	if err := c.Call(ctx, chatUpdateMethod, p, &ret); err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// DeleteMessage deletes the message of the timestamp, which the bot posted.
+// The channel is the ID, or the name which starts with "#".
+// see. https://api.slack.com/methods/chat.delete
+func (c *Client) DeleteMessage(ctx context.Context, channel, ts string) error {
+	if ts == "" {
+		return errors.New("timestamp of the message to delete is empty")
+	}
+	channelID, err := c.channels.Resolve(ctx, channel)
+	if err != nil {
+		return err
+	}
+	p := messageParams{
+		Channel: channelID,
+		TS:      ts,
+	}
+	return c.Call(ctx, chatDeleteMethod, p, nil)
+}
+
+// ephemeralResponse is the response of the chat.postEphemeral API.
+type ephemeralResponse struct {
+	MessageTS string `json:"message_ts"`
+}
+
+// PostEphemeral sends the message visible only to the user in the channel.
+// The channel is the ID, or the name which starts with "#".
+// The timestamp of the response can not be used to update or delete the message.
+// see. https://api.slack.com/methods/chat.postEphemeral
+func (c *Client) PostEphemeral(ctx context.Context, channel, userID, msg string, opts ...MessageOption) (*MessageResponse, error) {
+	if userID == "" {
+		return nil, errors.New("user of the ephemeral message is empty")
+	}
+	p, err := c.newMessageParams(ctx, channel, msg, opts)
+	if err != nil {
+		return nil, err
+	}
+	p.User = userID
+	var r ephemeralResponse
+	if err := c.Call(ctx, chatPostEphemeralMethod, p, &r); err != nil {
+		return nil, err
+	}
+	return &MessageResponse{OK: true, Channel: p.Channel, TS: r.MessageTS}, nil
+}
+
+// MeMessage sends the /me message, which is displayed in italics, to the channel.
+// The channel is the ID, or the name which starts with "#".
+// chat.meMessage takes only the text, so it has no options.
+// see. https://api.slack.com/methods/chat.meMessage
+func (c *Client) MeMessage(ctx context.Context, channel, msg string) (*MessageResponse, error) {
+	p, err := c.newMessageParams(ctx, channel, msg, nil)
+	if err != nil {
+		return nil, err
+	}
+	var ret MessageResponse
+	if err := c.Call(ctx, chatMeMessageMethod, p, &ret); err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
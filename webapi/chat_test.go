@@ -0,0 +1,106 @@
+package webapi_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ikawaha/slackbot/blocks"
+	"github.com/ikawaha/slackbot/slacktest"
+	"github.com/ikawaha/slackbot/webapi"
+)
+
+func TestClient_PostMessage(t *testing.T) {
+	ctx := context.Background()
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	srv.AddChannel(webapi.Channel{ID: "C1", Name: "general"})
+	c := newClient(t, srv)
+
+	r, err := c.PostMessage(ctx, "#general", "hello", webapi.ThreadTS("1.000001"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.Channel != "C1" || r.TS == "" {
+		t.Errorf("unexpected response: %+v", r)
+	}
+	ms := srv.PostedMessages()
+	if len(ms) != 1 {
+		t.Fatalf("want 1 message, got %+v", ms)
+	}
+	if m := ms[0]; m.Method != "chat.postMessage" || m.Channel != "C1" || m.Text != "hello" || m.ThreadTS != "1.000001" || m.TS != r.TS {
+		t.Errorf("unexpected message: %+v", m)
+	}
+
+	u, err := c.UpdateMessage(ctx, "#general", r.TS, "updated", webapi.Blocks(blocks.NewSection(blocks.Markdown("*updated*"))))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if u.TS != r.TS || u.Channel != "C1" {
+		t.Errorf("unexpected response: %+v", u)
+	}
+	if m, ok := srv.Message("C1", r.TS); !ok || m.Text != "updated" || len(m.Blocks) == 0 {
+		t.Errorf("want updated message, got %+v, %v", m, ok)
+	}
+	if err := c.DeleteMessage(ctx, "C1", r.TS); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := srv.Message("C1", r.TS); ok {
+		t.Error("want the message deleted")
+	}
+	if _, err := c.UpdateMessage(ctx, "C1", r.TS, "again"); !errors.Is(err, webapi.ErrMessageNotFound) {
+		t.Errorf("want ErrMessageNotFound, got %v", err)
+	}
+	if err := c.DeleteMessage(ctx, "C1", r.TS); !errors.Is(err, webapi.ErrMessageNotFound) {
+		t.Errorf("want ErrMessageNotFound, got %v", err)
+	}
+	if _, err := c.UpdateMessage(ctx, "C1", "", "no ts"); err == nil {
+		t.Error("want error without the timestamp")
+	}
+}
+
+func TestClient_PostMessage_ChannelNotFound(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	c := newClient(t, srv)
+
+	if _, err := c.PostMessage(context.Background(), "#unknown", "hello"); !errors.Is(err, webapi.ErrChannelNotFound) {
+		t.Errorf("want ErrChannelNotFound, got %v", err)
+	}
+	if _, err := c.UpdateMessage(context.Background(), "#unknown", "1.000001", "hello"); !errors.Is(err, webapi.ErrChannelNotFound) {
+		t.Errorf("want ErrChannelNotFound, got %v", err)
+	}
+	if n := len(srv.PostedMessages()); n != 0 {
+		t.Errorf("want no messages, got %d", n)
+	}
+}
+
+func TestClient_PostEphemeral(t *testing.T) {
+	ctx := context.Background()
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	srv.AddChannel(webapi.Channel{ID: "C1", Name: "general"})
+	c := newClient(t, srv)
+
+	r, err := c.PostEphemeral(ctx, "#general", "U1", "only you", webapi.ThreadTS("4.000001"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	es, err := srv.WaitEphemerals(waitContext(t), 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e := es[0]; e.Channel != "C1" || e.User != "U1" || e.Text != "only you" || e.ThreadTS != "4.000001" || e.TS != r.TS {
+		t.Errorf("unexpected ephemeral message: %+v, response: %+v", e, r)
+	}
+	if n := len(srv.PostedMessages()); n != 0 {
+		t.Errorf("want the ephemeral message not to be posted to the channel, got %d", n)
+	}
+
+	if _, err := c.MeMessage(ctx, "C1", "waves"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ms := srv.PostedMessages(); len(ms) != 1 || ms[0].Method != "chat.meMessage" || ms[0].Text != "waves" {
+		t.Errorf("want the me message, got %+v", ms)
+	}
+}
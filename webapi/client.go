@@ -7,7 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"io/ioutil"
+	"log"
 	"mime/multipart"
 	"net/http"
 	"net/url"
@@ -22,85 +22,154 @@ const (
 )
 
 const (
-	postMessageEndpoint = "https://slack.com/api/chat.postMessage"
-	filesUploadEndpoint = "https://slack.com/api/files.upload"
-	usersListEndpoint   = "https://slack.com/api/users.list"
+	// DefaultBaseURL is the base URL of the Slack Web API.
+	DefaultBaseURL = "https://slack.com/api/"
+)
+
+const (
+	postMessageMethod = "chat.postMessage"
+	filesUploadMethod = "files.upload"
+	usersListMethod   = "users.list"
+	usersInfoMethod   = "users.info"
+
+	// responseURLMethod is the method name of the APIError of the response URL.
+	responseURLMethod = "response_url"
 )
 
 // Client represents a Slack client for Web API.
 type Client struct {
 	mux        sync.Mutex
 	token      string
+	baseURL    string
 	httpclient *http.Client
-	usersCache map[string]User
-	debug      bool
+	users      *UserDirectory
+	usersTTL   time.Duration
+	cacheUsers bool
+	cacheStore CacheStore
+
+	channels      *ChannelDirectory
+	channelsTTL   time.Duration
+	cacheChannels bool
+	debug         bool
+
+	limiter             *limiter
+	throttle            bool
+	maxRateLimitRetries int
+	retryPolicy         RetryPolicy
+	dedupPostMessage    bool
+	pageLimit           int
+
+	// ctx is the context of the background refreshes of the caches, which is canceled by Close.
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
 // New creates a client with a bot token.
 func New(token string, opts ...Option) (*Client, error) {
 	ret := Client{
-		token: token,
+		token:   token,
+		baseURL: DefaultBaseURL,
 		httpclient: &http.Client{
 			Timeout: DefaultTimeout,
 		},
+		limiter:             newLimiter(),
+		throttle:            true,
+		maxRateLimitRetries: DefaultMaxRateLimitRetries,
+		retryPolicy:         DefaultRetryPolicy,
+		pageLimit:           DefaultPageLimit,
+		cacheStore:          NewMemoryStore(),
 	}
 	for _, opt := range opts {
 		if err := opt(&ret); err != nil {
 			return nil, err
 		}
 	}
+	ret.ctx, ret.cancel = context.WithCancel(context.Background())
+	ret.users = newUserDirectory(&ret, ret.cacheStore, ret.usersTTL)
+	ret.channels = newChannelDirectory(&ret, ret.cacheStore, ret.channelsTTL)
+	if ret.cacheUsers {
+		if err := warmUp(context.TODO(), ret.users); err != nil {
+			_ = ret.Close()
+			return nil, err
+		}
+	}
+	if ret.cacheChannels {
+		if err := warmUp(context.TODO(), ret.channels); err != nil {
+			_ = ret.Close()
+			return nil, err
+		}
+	}
 	return &ret, nil
 }
 
+// Close cancels the background refreshes of the caches and waits for them to stop.
+// The client can still call the Web API after closing, but the caches are no longer refreshed in the background.
+func (c *Client) Close() error {
+	c.cancel()
+	c.users.close()
+	c.channels.close()
+	return nil
+}
+
+// endpoint returns the URL of the Web API method.
+func (c *Client) endpoint(method string) string {
+	return c.baseURL + method
+}
+
 // PostMessage sends a message to the Slack channel.
+// The channel is the ID, or the name which starts with "#".
 // see. https://api.slack.com/methods/chat.postMessage
-func (c *Client) PostMessage(ctx context.Context, channelID string, msg string) (*MessageResponse, error) {
-	body := url.Values{
-		"token":   {c.token},
-		"channel": {channelID},
-		"text":    {msg},
-	}.Encode()
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, postMessageEndpoint, strings.NewReader(body))
+func (c *Client) PostMessage(ctx context.Context, channel string, msg string, opts ...MessageOption) (*MessageResponse, error) {
+	p, err := c.newMessageParams(ctx, channel, msg, opts)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if c.dedupPostMessage {
+		return c.postMessageWithDedup(ctx, p)
+	}
+	return c.postMessage(ctx, p)
+}
 
-	resp, err := c.httpclient.Do(req)
+// newMessageParams creates the parameters of the message to the channel, resolving the channel name to the ID.
+func (c *Client) newMessageParams(ctx context.Context, channel, msg string, opts []MessageOption) (messageParams, error) {
+	channelID, err := c.channels.Resolve(ctx, channel)
 	if err != nil {
-		return nil, fmt.Errorf("slack chat.postMessage failed: %w", err)
+		return messageParams{}, err
 	}
-	defer resp.Body.Close()
-	b, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("response body read error: %w", err)
+	p := messageParams{
+		Channel: channelID,
+		Text:    msg,
 	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("slack chat.postMessage failed: %v, %q", resp.Status, body)
+	for _, opt := range opts {
+		if err := opt(&p); err != nil {
+			return messageParams{}, err
+		}
 	}
+	return p, nil
+}
+
+func (c *Client) postMessage(ctx context.Context, p messageParams) (*MessageResponse, error) {
 	var ret MessageResponse
-	if err := json.Unmarshal(b, &ret); err != nil {
-		return nil, fmt.Errorf("response body unmarshal error: body=%q, %w", string(b), err)
-	}
-	if !ret.OK {
-		if ret.Error == "missing_scope" {
-			return nil, fmt.Errorf("%s: needed: %q, provided: %q", ret.Error, ret.Needed, ret.Provided)
-		}
-		return nil, errors.New(ret.Error)
+	if _, err := c.call(ctx, postMessageMethod, p.Channel, p, &ret); err != nil {
+		return nil, err
 	}
 	return &ret, nil
 }
 
 // RespondToCommand responds to the Slack command.
-func (c *Client) RespondToCommand(ctx context.Context, responseURL string, msg string, visible bool) error {
-	var responseType string
+func (c *Client) RespondToCommand(ctx context.Context, responseURL string, msg string, visible bool, opts ...MessageOption) error {
+	params := messageParams{
+		Text: msg,
+	}
 	if visible {
-		responseType = "in_channel"
+		params.ResponseType = "in_channel"
+	}
+	for _, opt := range opts {
+		if err := opt(&params); err != nil {
+			return err
+		}
 	}
-	p, err := json.Marshal(map[string]string{
-		"response_type": responseType,
-		"text":          msg,
-	})
+	p, err := json.Marshal(params)
 	if err != nil {
 		return fmt.Errorf("request body marshal error: %w", err)
 	}
@@ -110,14 +179,21 @@ func (c *Client) RespondToCommand(ctx context.Context, responseURL string, msg s
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpclient.Do(req)
+	resp, err := c.do(req, responseURLMethod, "")
 	if err != nil {
 		return fmt.Errorf("command response failed: %w", err)
 	}
 	defer resp.Body.Close()
-	io.Copy(io.Discard, resp.Body) // nolint:errcheck
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("response body read error: %w", err)
+	}
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("slack command response failed: %v, %q", resp.Status, string(p))
+		return &APIError{
+			Method:     responseURLMethod,
+			Code:       strings.TrimSpace(string(b)),
+			StatusCode: resp.StatusCode,
+		}
 	}
 	return nil
 }
@@ -125,9 +201,6 @@ func (c *Client) RespondToCommand(ctx context.Context, responseURL string, msg s
 // UploadImage uploads an image by files.upload API.
 // see. https://api.slack.com/methods/files.upload
 func (c *Client) UploadImage(ctx context.Context, channels []string, title, fileName, fileType, comment string, img io.Reader) error {
-	if c.token == "" {
-		return fmt.Errorf("slack token is empty")
-	}
 	var buf bytes.Buffer
 	mw := multipart.NewWriter(&buf)
 	part, err := mw.CreateFormFile("file", fileName)
@@ -139,7 +212,6 @@ func (c *Client) UploadImage(ctx context.Context, channels []string, title, file
 	}
 	// for slack settings
 	settings := map[string]string{
-		"token":           c.token,
 		"channels":        strings.Join(channels, ","),
 		"filetype":        fileType,
 		"title":           title,
@@ -154,60 +226,31 @@ func (c *Client) UploadImage(ctx context.Context, channels []string, title, file
 		return err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", filesUploadEndpoint, &buf)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint(filesUploadMethod), &buf)
 	if err != nil {
 		return fmt.Errorf("slack files.uplad new request error, %w", err)
 	}
 	req.Header.Set("Content-Type", mw.FormDataContentType())
-	cl := &http.Client{Timeout: 10 * time.Second}
-	resp, err := cl.Do(req)
-	if err != nil {
-		return fmt.Errorf("slack files.upload error, %w", err)
-	}
-	defer resp.Body.Close()
-	b, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("response body read error: %w", err)
-	}
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("slack files.upload status error: %s, %q", resp.Status, string(b))
-	}
-	return nil
+	_, err = c.send(req, filesUploadMethod, "", nil)
+	return err
 }
 
-// UsersList lists all users in a Slack team.
+// UsersList lists all users in a Slack team, following the cursor over the pages.
 // see. https://api.slack.com/methods/users.list
 func (c *Client) UsersList(ctx context.Context) ([]User, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, usersListEndpoint, nil)
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Authorization", "Bearer "+c.token)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpclient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("slack chat.postMessage failed: %w", err)
-	}
-	defer resp.Body.Close()
-	b, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("response body read error: %w", err)
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("slack chat.postMessage failed: %v", resp.Status)
-	}
-	var ul UsersListResponse
-	if err := json.Unmarshal(b, &ul); err != nil {
-		return nil, fmt.Errorf("response body unmarshal error: body=%q, %w", string(b), err)
-	}
-	if !ul.OK {
-		if ul.Error == "missing_scope" {
-			return nil, fmt.Errorf("%s: needed: %q, provided: %q", ul.Error, ul.Needed, ul.Provided)
+	var ret []User
+	p := c.NewPaginator(usersListMethod, url.Values{"include_locale": {"true"}}, 0)
+	for {
+		var page UsersListResponse
+		if !p.Next(ctx, &page) {
+			break
 		}
-		return nil, errors.New(ul.Error)
+		ret = append(ret, page.Members...)
 	}
-	return ul.Members, nil
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	return ret, nil
 }
 
 // Users lists all users in a Slack team and returns it's userID map.
@@ -223,33 +266,83 @@ func (c *Client) Users(ctx context.Context) (map[string]User, error) {
 	return ret, nil
 }
 
-// RefreshUsersCache updates the client's cached user map.
-func (c *Client) RefreshUsersCache(ctx context.Context) error {
-	us, err := c.Users(ctx)
-	if err != nil {
+// UsersInfo gets the information about the user.
+// see. https://api.slack.com/methods/users.info
+func (c *Client) UsersInfo(ctx context.Context, id string) (*User, error) {
+	var r UsersInfoResponse
+	if err := c.Call(ctx, usersInfoMethod, url.Values{"user": {id}, "include_locale": {"true"}}, &r); err != nil {
+		return nil, err
+	}
+	return &r.User, nil
+}
+
+// UserDirectory returns the client's user cache.
+func (c *Client) UserDirectory() *UserDirectory {
+	return c.users
+}
+
+// cache is the cache which is loaded from the cache store or refreshed by the Web API.
+type cache interface {
+	Load(ctx context.Context) error
+	Refresh(ctx context.Context) error
+	Save(ctx context.Context) error
+	refreshInBackground()
+}
+
+// warmUp loads the cache from the cache store, or refreshes it if the store has no entries.
+// The loaded cache is served at once and refreshed in the background, because it may be
+// saved long ago by the previous run.
+func warmUp(ctx context.Context, c cache) error {
+	err := c.Load(ctx)
+	if err == nil {
+		c.refreshInBackground()
+		return nil
+	}
+	if !errors.Is(err, ErrCacheMiss) {
+		log.Printf("cache load error: %v", err)
+	}
+	return c.Refresh(ctx)
+}
+
+// SaveCache saves the client's caches to the cache store.
+func (c *Client) SaveCache(ctx context.Context) error {
+	if err := c.users.Save(ctx); err != nil {
 		return err
 	}
-	defer c.mux.Unlock()
-	c.mux.Lock()
-	c.usersCache = us
-	return nil
+	return c.channels.Save(ctx)
+}
+
+// ChannelDirectory returns the client's channel cache.
+func (c *Client) ChannelDirectory() *ChannelDirectory {
+	return c.channels
+}
+
+// RefreshChannelsCache updates the client's channel cache.
+func (c *Client) RefreshChannelsCache(ctx context.Context) error {
+	return c.channels.Refresh(ctx)
+}
+
+// RefreshUsersCache updates the client's user cache.
+func (c *Client) RefreshUsersCache(ctx context.Context) error {
+	return c.users.Refresh(ctx)
 }
 
 // User returns the user corresponding to user ID from the client's user cache.
+// It only reads the cache; use GetUser to look up the user who is not cached yet.
 func (c *Client) User(id string) (User, bool) {
-	u, ok := c.usersCache[id]
-	if ok {
-		return u, true
-	}
-	return u, false
+	return c.users.Lookup(id)
+}
+
+// GetUser returns the user corresponding to user ID. If the user is not cached,
+// GetUser looks up it by users.info and caches it.
+// see. https://api.slack.com/methods/users.info
+func (c *Client) GetUser(ctx context.Context, id string) (User, error) {
+	return c.users.Get(ctx, id)
 }
 
 // UserID returns the userID corresponding to the username from the client's user cache.
+// It only reads the cache, because users.info can not look up a user by the name.
 func (c *Client) UserID(name string) string {
-	for k, v := range c.usersCache {
-		if !v.Deleted && v.Name == name {
-			return k
-		}
-	}
-	return ""
+	u, _ := c.users.ByName(name)
+	return u.ID
 }
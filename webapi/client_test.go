@@ -0,0 +1,153 @@
+package webapi_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ikawaha/slackbot/slacktest"
+	"github.com/ikawaha/slackbot/webapi"
+)
+
+func newClient(t *testing.T, srv *slacktest.Server, opts ...webapi.Option) *webapi.Client {
+	t.Helper()
+	c, err := webapi.New("xoxb-token", append([]webapi.Option{webapi.BaseURL(srv.URL())}, opts...)...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return c
+}
+
+func waitContext(t *testing.T) context.Context {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func TestClient_RespondToCommand(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	c := newClient(t, srv)
+	url := strings.TrimSuffix(srv.URL(), "/api/") + "/commands/1"
+
+	if err := c.RespondToCommand(context.Background(), url, "visible", true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.RespondToCommand(context.Background(), url, "ephemeral", false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rs, err := srv.WaitCommandResponses(waitContext(t), 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rs[0].Text != "visible" || rs[0].ResponseType != "in_channel" || rs[0].URL != url {
+		t.Errorf("unexpected response: %+v", rs[0])
+	}
+	if rs[1].Text != "ephemeral" || rs[1].ResponseType != "" {
+		t.Errorf("unexpected response: %+v", rs[1])
+	}
+}
+
+func TestClient_UploadImage(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	c := newClient(t, srv)
+
+	img := []byte("\x89PNG")
+	if err := c.UploadImage(context.Background(), []string{"C1", "C2"}, "title", "a.png", "png", "look", bytes.NewReader(img)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	us, err := srv.WaitUploads(waitContext(t), 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	u := us[0]
+	if strings.Join(u.Channels, ",") != "C1,C2" || u.Title != "title" || u.FileName != "a.png" || u.FileType != "png" || u.InitialComment != "look" || !bytes.Equal(u.Content, img) {
+		t.Errorf("unexpected upload: %+v", u)
+	}
+}
+
+func TestClient_RetryAfter(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	srv.AddUser(webapi.User{ID: "U1", Name: "alice"})
+	var calls int32
+	srv.HandleMethod("users.info", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= 2 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"ok":true,"user":{"id":"U1","name":"alice"}}`)
+	})
+
+	c := newClient(t, srv)
+	u, err := c.UsersInfo(context.Background(), "U1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if u.Name != "alice" || atomic.LoadInt32(&calls) != 3 {
+		t.Errorf("want alice after 3 calls, got %+v after %d calls", u, calls)
+	}
+	if got := c.RateLimitStats(); got.RateLimited != 2 || got.RetryAfterWait != 0 || got.Throttled != 0 {
+		t.Errorf("unexpected stats: %+v", got)
+	}
+
+	atomic.StoreInt32(&calls, 0)
+	c = newClient(t, srv, webapi.MaxRateLimitRetries(1))
+	if _, err := c.UsersInfo(context.Background(), "U1"); !errors.Is(err, webapi.ErrRateLimited) {
+		t.Errorf("want ErrRateLimited, got %v", err)
+	}
+	if got := c.RateLimitStats(); got.RateLimited != 1 {
+		t.Errorf("want 1 rate limited, got %+v", got)
+	}
+}
+
+func TestClient_ThrottleRetries(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	var calls int32
+	srv.HandleMethod("users.info", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"ok":true,"user":{"id":"U1","name":"alice"}}`)
+	})
+
+	// a token per 100ms, with the burst of 1.
+	c := newClient(t, srv,
+		webapi.RateLimit("users.info", webapi.Limit{PerMinute: 600, Burst: 1}),
+		webapi.SetRetryPolicy(webapi.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}),
+	)
+	begin := time.Now()
+	if _, err := c.UsersInfo(context.Background(), "U1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d := time.Since(begin); d < 90*time.Millisecond {
+		t.Errorf("want the retry to wait for the throttling, got %v", d)
+	}
+	got := c.RateLimitStats()
+	if got.Throttled != 1 || got.ThrottledWait <= 0 {
+		t.Errorf("want the retry to be throttled, got %+v", got)
+	}
+
+	c = newClient(t, srv, webapi.DisableThrottle(), webapi.RateLimit("users.info", webapi.Limit{PerMinute: 1, Burst: 1}))
+	for i := 0; i < 3; i++ {
+		if _, err := c.UsersInfo(context.Background(), "U1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if got := c.RateLimitStats(); got != (webapi.RateLimitStats{}) {
+		t.Errorf("want no throttling, got %+v", got)
+	}
+}
@@ -0,0 +1,248 @@
+package webapi
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	conversationsListMethod    = "conversations.list"
+	conversationsInfoMethod    = "conversations.info"
+	conversationsMembersMethod = "conversations.members"
+	conversationsJoinMethod    = "conversations.join"
+	conversationsOpenMethod    = "conversations.open"
+	conversationsCreateMethod  = "conversations.create"
+	conversationsInviteMethod  = "conversations.invite"
+	conversationsArchiveMethod = "conversations.archive"
+)
+
+// ConversationType is the type of the conversations to list.
+type ConversationType string
+
+// Conversation types.
+const (
+	PublicChannel  ConversationType = "public_channel"
+	PrivateChannel ConversationType = "private_channel"
+	MPIM           ConversationType = "mpim" // multi-person direct message
+	IM             ConversationType = "im"   // direct message
+)
+
+// Channel represents the Slack conversation: a channel, a direct message or a multi-person direct message.
+// see. https://api.slack.com/types/conversation
+type Channel struct {
+	ID                 string   `json:"id,omitempty"`
+	Name               string   `json:"name,omitempty"`
+	NameNormalized     string   `json:"name_normalized,omitempty"`
+	IsChannel          bool     `json:"is_channel,omitempty"`
+	IsGroup            bool     `json:"is_group,omitempty"`
+	IsIM               bool     `json:"is_im,omitempty"`
+	IsMPIM             bool     `json:"is_mpim,omitempty"`
+	IsPrivate          bool     `json:"is_private,omitempty"`
+	IsArchived         bool     `json:"is_archived,omitempty"`
+	IsGeneral          bool     `json:"is_general,omitempty"`
+	IsShared           bool     `json:"is_shared,omitempty"`
+	IsExtShared        bool     `json:"is_ext_shared,omitempty"`
+	IsOrgShared        bool     `json:"is_org_shared,omitempty"`
+	IsMember           bool     `json:"is_member,omitempty"`
+	Created            int64    `json:"created,omitempty"` // unix time
+	Creator            string   `json:"creator,omitempty"`
+	Updated            int64    `json:"updated,omitempty"` // unix time in milliseconds
+	ContextTeamID      string   `json:"context_team_id,omitempty"`
+	Topic              Topic    `json:"topic"`
+	Purpose            Topic    `json:"purpose"`
+	PreviousNames      []string `json:"previous_names,omitempty"`
+	NumMembers         int      `json:"num_members,omitempty"`
+	Locale             string   `json:"locale,omitempty"`
+	User               string   `json:"user,omitempty"` // the other user of the direct message
+	IsUserDeleted      bool     `json:"is_user_deleted,omitempty"`
+	Priority           float64  `json:"priority,omitempty"`
+	LastRead           string   `json:"last_read,omitempty"`
+	UnreadCount        int      `json:"unread_count,omitempty"`
+	UnreadCountDisplay int      `json:"unread_count_display,omitempty"`
+}
+
+// Topic represents the topic or the purpose of the channel.
+type Topic struct {
+	Value   string `json:"value"`
+	Creator string `json:"creator"`
+	LastSet int64  `json:"last_set"` // unix time
+}
+
+// CreatedAt returns the time when the channel was created.
+func (c Channel) CreatedAt() time.Time {
+	return time.Unix(c.Created, 0)
+}
+
+// ConversationsListResponse is the response of the conversations.list API.
+type ConversationsListResponse struct {
+	OK       bool      `json:"ok"`
+	Error    string    `json:"error"`
+	Needed   string    `json:"needed"`
+	Provided string    `json:"provided"`
+	Channels []Channel `json:"channels"`
+}
+
+// ConversationResponse is the response of the conversations API which returns a channel.
+type ConversationResponse struct {
+	OK          bool    `json:"ok"`
+	Error       string  `json:"error"`
+	Needed      string  `json:"needed"`
+	Provided    string  `json:"provided"`
+	Channel     Channel `json:"channel"`
+	NoOp        bool    `json:"no_op,omitempty"`
+	AlreadyOpen bool    `json:"already_open,omitempty"`
+}
+
+// ConversationsMembersResponse is the response of the conversations.members API.
+type ConversationsMembersResponse struct {
+	OK       bool     `json:"ok"`
+	Error    string   `json:"error"`
+	Needed   string   `json:"needed"`
+	Provided string   `json:"provided"`
+	Members  []string `json:"members"`
+}
+
+// ConversationsList lists all the conversations of the types in a Slack team, following the cursor over the pages.
+// If no type is given, it lists the public channels.
+// required scopes: `channels:read`, `groups:read`, `im:read` or `mpim:read` for the types
+// see. https://api.slack.com/methods/conversations.list
+func (c *Client) ConversationsList(ctx context.Context, excludeArchived bool, types ...ConversationType) ([]Channel, error) {
+	params := url.Values{
+		"exclude_archived": {strconv.FormatBool(excludeArchived)},
+	}
+	if len(types) > 0 {
+		ts := make([]string, 0, len(types))
+		for _, v := range types {
+			ts = append(ts, string(v))
+		}
+		params.Set("types", strings.Join(ts, ","))
+	}
+	var ret []Channel
+	p := c.NewPaginator(conversationsListMethod, params, 0)
+	for {
+		var page ConversationsListResponse
+		if !p.Next(ctx, &page) {
+			break
+		}
+		ret = append(ret, page.Channels...)
+	}
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// ConversationsInfo gets the information about the conversation.
+// The channel is the ID, or the name which starts with "#".
+// see. https://api.slack.com/methods/conversations.info
+func (c *Client) ConversationsInfo(ctx context.Context, channel string) (*Channel, error) {
+	channelID, err := c.channels.Resolve(ctx, channel)
+	if err != nil {
+		return nil, err
+	}
+	return c.callConversation(ctx, conversationsInfoMethod, url.Values{
+		"channel":             {channelID},
+		"include_num_members": {"true"},
+	})
+}
+
+// ConversationsMembers lists the user IDs of the members of the conversation, following the cursor over the pages.
+// The channel is the ID, or the name which starts with "#".
+// see. https://api.slack.com/methods/conversations.members
+func (c *Client) ConversationsMembers(ctx context.Context, channel string) ([]string, error) {
+	channelID, err := c.channels.Resolve(ctx, channel)
+	if err != nil {
+		return nil, err
+	}
+	var ret []string
+	p := c.NewPaginator(conversationsMembersMethod, url.Values{"channel": {channelID}}, 0)
+	for {
+		var page ConversationsMembersResponse
+		if !p.Next(ctx, &page) {
+			break
+		}
+		ret = append(ret, page.Members...)
+	}
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// ConversationsJoin joins the bot to the existing public channel.
+// The channel is the ID, or the name which starts with "#".
+// required scopes: `channels:join`
+// see. https://api.slack.com/methods/conversations.join
+func (c *Client) ConversationsJoin(ctx context.Context, channel string) (*Channel, error) {
+	channelID, err := c.channels.Resolve(ctx, channel)
+	if err != nil {
+		return nil, err
+	}
+	return c.callConversation(ctx, conversationsJoinMethod, url.Values{"channel": {channelID}})
+}
+
+// ConversationsOpen opens the direct message with the user, or the multi-person direct message with the users.
+// If the conversation is already open, it returns the existing one.
+// required scopes: `im:write` or `mpim:write`
+// see. https://api.slack.com/methods/conversations.open
+func (c *Client) ConversationsOpen(ctx context.Context, userIDs ...string) (*Channel, error) {
+	if len(userIDs) == 0 {
+		return nil, errors.New("no users to open the conversation with")
+	}
+	return c.callConversation(ctx, conversationsOpenMethod, url.Values{
+		"users":     {strings.Join(userIDs, ",")},
+		"return_im": {"true"},
+	})
+}
+
+// ConversationsCreate creates the public or private channel.
+// required scopes: `channels:manage` or `groups:write`
+// see. https://api.slack.com/methods/conversations.create
+func (c *Client) ConversationsCreate(ctx context.Context, name string, private bool) (*Channel, error) {
+	return c.callConversation(ctx, conversationsCreateMethod, url.Values{
+		"name":       {name},
+		"is_private": {strconv.FormatBool(private)},
+	})
+}
+
+// ConversationsInvite invites the users to the channel.
+// The channel is the ID, or the name which starts with "#".
+// required scopes: `channels:manage` or `groups:write`
+// see. https://api.slack.com/methods/conversations.invite
+func (c *Client) ConversationsInvite(ctx context.Context, channel string, userIDs ...string) (*Channel, error) {
+	if len(userIDs) == 0 {
+		return nil, errors.New("no users to invite")
+	}
+	channelID, err := c.channels.Resolve(ctx, channel)
+	if err != nil {
+		return nil, err
+	}
+	return c.callConversation(ctx, conversationsInviteMethod, url.Values{
+		"channel": {channelID},
+		"users":   {strings.Join(userIDs, ",")},
+	})
+}
+
+// ConversationsArchive archives the conversation.
+// The channel is the ID, or the name which starts with "#".
+// required scopes: `channels:manage` or `groups:write`
+// see. https://api.slack.com/methods/conversations.archive
+func (c *Client) ConversationsArchive(ctx context.Context, channel string) error {
+	channelID, err := c.channels.Resolve(ctx, channel)
+	if err != nil {
+		return err
+	}
+	return c.Call(ctx, conversationsArchiveMethod, url.Values{"channel": {channelID}}, nil)
+}
+
+func (c *Client) callConversation(ctx context.Context, method string, params url.Values) (*Channel, error) {
+	var r ConversationResponse
+	if err := c.Call(ctx, method, params, &r); err != nil {
+		return nil, err
+	}
+	return &r.Channel, nil
+}
@@ -0,0 +1,524 @@
+package webapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// directoryCache is the loading, saving and refreshing of the cache shared by the directories.
+// The directory embeds it with the functions to fetch and index its entries, which are the pointer
+// to the slice of the entries, and guards its indexes by the same lock.
+type directoryCache struct {
+	mux         sync.RWMutex
+	store       CacheStore
+	kind        string
+	ttl         time.Duration
+	refreshedAt time.Time
+	refreshing  bool
+
+	// ctx is the context of the background refreshes, which is canceled when the client is closed.
+	ctx        context.Context
+	background sync.WaitGroup
+	closed     bool
+
+	// fetch gets all the entries by the Web API.
+	fetch func(ctx context.Context) (interface{}, error)
+	// empty returns the empty entries to decode the entries of the store into.
+	empty func() interface{}
+	// reindex replaces the indexes with the entries. It is called under the lock.
+	reindex func(entries interface{})
+	// entries returns the cached entries.
+	entries func() interface{}
+}
+
+func (d *directoryCache) refresh(ctx context.Context) error {
+	v, err := d.fetch(ctx)
+	if err != nil {
+		return err
+	}
+	at := time.Now()
+	d.replace(v, at)
+	if err := d.store.Save(ctx, d.kind, v, at); err != nil {
+		log.Printf("%s cache save error: %v", d.kind, err)
+	}
+	return nil
+}
+
+func (d *directoryCache) load(ctx context.Context) error {
+	v := d.empty()
+	at, err := d.store.Load(ctx, d.kind, v)
+	if err != nil {
+		return err
+	}
+	d.replace(v, at)
+	return nil
+}
+
+func (d *directoryCache) save(ctx context.Context) error {
+	d.mux.RLock()
+	at := d.refreshedAt
+	d.mux.RUnlock()
+	if at.IsZero() {
+		return nil
+	}
+	return d.store.Save(ctx, d.kind, d.entries(), at)
+}
+
+func (d *directoryCache) replace(v interface{}, at time.Time) {
+	defer d.mux.Unlock()
+	d.mux.Lock()
+	d.reindex(v)
+	d.refreshedAt = at
+}
+
+// refreshedWithin returns true, if the cache has been refreshed within the duration.
+func (d *directoryCache) refreshedWithin(dur time.Duration) bool {
+	d.mux.RLock()
+	defer d.mux.RUnlock()
+	return time.Since(d.refreshedAt) < dur
+}
+
+// refreshIfStale starts refreshing the directory in the background, if the TTL has passed since the last refresh.
+// It is called on each lookup, and no timer refreshes the directory without lookups.
+func (d *directoryCache) refreshIfStale() {
+	d.mux.RLock()
+	stale := d.ttl > 0 && !d.refreshing && time.Since(d.refreshedAt) > d.ttl
+	d.mux.RUnlock()
+	if stale {
+		d.refreshInBackground()
+	}
+}
+
+// refreshInBackground starts refreshing the directory in the background, unless it is being refreshed
+// or the client has been closed. The entries are served from the cache while refreshing.
+func (d *directoryCache) refreshInBackground() {
+	d.mux.Lock()
+	if d.refreshing || d.closed {
+		d.mux.Unlock()
+		return
+	}
+	d.refreshing = true
+	d.background.Add(1)
+	d.mux.Unlock()
+	go func() {
+		defer d.background.Done()
+		err := d.refresh(d.ctx)
+		if err != nil && d.ctx.Err() == nil {
+			log.Printf("%s cache refresh error: %v", d.kind, err)
+		}
+		defer d.mux.Unlock()
+		d.mux.Lock()
+		d.refreshing = false
+		if err != nil {
+			// wait for the next TTL instead of retrying on every lookup.
+			d.refreshedAt = time.Now()
+		}
+	}()
+}
+
+// close stops starting the background refreshes and waits for the running one to finish.
+func (d *directoryCache) close() {
+	d.mux.Lock()
+	d.closed = true
+	d.mux.Unlock()
+	d.background.Wait()
+}
+
+// UserDirectory is the cache of the users in a Slack team indexed by the ID, the name, the display name and the email.
+// It is safe for concurrent use.
+// The cache is refreshed lazily: when the TTL has passed, the next lookup starts the refresh in the background
+// and returns the cached user meanwhile.
+type UserDirectory struct {
+	directoryCache
+	client        *Client
+	byID          map[string]User
+	byName        map[string]string
+	byDisplayName map[string]string
+	byEmail       map[string]string
+}
+
+func newUserDirectory(c *Client, store CacheStore, ttl time.Duration) *UserDirectory {
+	ret := &UserDirectory{
+		client: c,
+	}
+	ret.directoryCache = directoryCache{
+		store: store,
+		kind:  usersCacheKind,
+		ttl:   ttl,
+		ctx:   c.ctx,
+		fetch: func(ctx context.Context) (interface{}, error) {
+			list, err := c.UsersList(ctx)
+			return &list, err
+		},
+		empty: func() interface{} { return &[]User{} },
+		reindex: func(v interface{}) {
+			ret.reset()
+			for _, u := range *v.(*[]User) {
+				ret.put(u)
+			}
+		},
+		entries: func() interface{} {
+			list := ret.Users()
+			return &list
+		},
+	}
+	ret.reset()
+	return ret
+}
+
+func (d *UserDirectory) reset() {
+	d.byID = map[string]User{}
+	d.byName = map[string]string{}
+	d.byDisplayName = map[string]string{}
+	d.byEmail = map[string]string{}
+}
+
+// Refresh replaces the cached users with all users in the Slack team, and saves them to the cache store.
+// required scopes: `users:read`, and `users:read.email` to index the emails
+func (d *UserDirectory) Refresh(ctx context.Context) error {
+	return d.refresh(ctx)
+}
+
+// Load replaces the cached users with the users saved in the cache store.
+// The loaded users are refreshed when the TTL has passed since they were saved.
+// It returns ErrCacheMiss if the store has no users.
+func (d *UserDirectory) Load(ctx context.Context) error {
+	return d.load(ctx)
+}
+
+// Save saves the cached users, including the updates by the events, to the cache store.
+// The cache which has never been loaded or refreshed is not saved.
+func (d *UserDirectory) Save(ctx context.Context) error {
+	return d.save(ctx)
+}
+
+// Put adds the user to the directory or updates it, e.g. by the user_change and team_join events.
+func (d *UserDirectory) Put(u User) {
+	if u.ID == "" {
+		return
+	}
+	defer d.mux.Unlock()
+	d.mux.Lock()
+	d.put(u)
+}
+
+func (d *UserDirectory) put(u User) {
+	if old, ok := d.byID[u.ID]; ok {
+		unindex(d.byName, old.Name, old.ID)
+		unindex(d.byDisplayName, old.Profile.DisplayName, old.ID)
+		unindex(d.byEmail, strings.ToLower(old.Profile.Email), old.ID)
+	}
+	d.byID[u.ID] = u
+	if u.Deleted {
+		// the deleted users are found only by the ID.
+		return
+	}
+	index(d.byName, u.Name, u.ID)
+	index(d.byDisplayName, u.Profile.DisplayName, u.ID)
+	index(d.byEmail, strings.ToLower(u.Profile.Email), u.ID)
+}
+
+func index(m map[string]string, key, id string) {
+	if key != "" {
+		m[key] = id
+	}
+}
+
+func unindex(m map[string]string, key, id string) {
+	if m[key] == id {
+		delete(m, key)
+	}
+}
+
+// Lookup returns the cached user of the ID.
+func (d *UserDirectory) Lookup(id string) (User, bool) {
+	d.refreshIfStale()
+	d.mux.RLock()
+	defer d.mux.RUnlock()
+	u, ok := d.byID[id]
+	return u, ok
+}
+
+// Get returns the user of the ID. If the user is not cached, Get looks up it by users.info and caches it.
+// see. https://api.slack.com/methods/users.info
+func (d *UserDirectory) Get(ctx context.Context, id string) (User, error) {
+	if u, ok := d.Lookup(id); ok {
+		return u, nil
+	}
+	u, err := d.client.UsersInfo(ctx, id)
+	if err != nil {
+		return User{}, err
+	}
+	d.Put(*u)
+	return *u, nil
+}
+
+// ByName returns the user of the name, which is the deprecated username of the user.
+func (d *UserDirectory) ByName(name string) (User, bool) {
+	return d.lookupBy(func() map[string]string { return d.byName }, name)
+}
+
+// ByDisplayName returns the user of the display name.
+// If some users have the same display name, it returns one of them.
+func (d *UserDirectory) ByDisplayName(name string) (User, bool) {
+	return d.lookupBy(func() map[string]string { return d.byDisplayName }, name)
+}
+
+// ByEmail returns the user of the email, case-insensitively.
+func (d *UserDirectory) ByEmail(email string) (User, bool) {
+	return d.lookupBy(func() map[string]string { return d.byEmail }, strings.ToLower(email))
+}
+
+// lookupBy looks up the user by the index, which is replaced by Refresh and must be read under the lock.
+func (d *UserDirectory) lookupBy(index func() map[string]string, key string) (User, bool) {
+	d.refreshIfStale()
+	d.mux.RLock()
+	defer d.mux.RUnlock()
+	id, ok := index()[key]
+	if !ok || key == "" {
+		return User{}, false
+	}
+	u, ok := d.byID[id]
+	return u, ok
+}
+
+// Users returns all the cached users ordered by the ID.
+func (d *UserDirectory) Users() []User {
+	d.mux.RLock()
+	ret := make([]User, 0, len(d.byID))
+	for _, v := range d.byID {
+		ret = append(ret, v)
+	}
+	d.mux.RUnlock()
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].ID < ret[j].ID
+	})
+	return ret
+}
+
+// Len returns the number of the cached users.
+func (d *UserDirectory) Len() int {
+	d.mux.RLock()
+	defer d.mux.RUnlock()
+	return len(d.byID)
+}
+
+// missRefreshInterval is the minimum interval of the refreshes of the channel cache by the names not found.
+const missRefreshInterval = time.Minute
+
+// ChannelDirectory is the cache of the public and private channels in a Slack team indexed by the ID and the name.
+// It is safe for concurrent use.
+// The cache is refreshed lazily: when the TTL has passed, the next lookup starts the refresh in the background
+// and returns the cached channel meanwhile.
+type ChannelDirectory struct {
+	directoryCache
+	client *Client
+	byID   map[string]Channel
+	byName map[string]string
+}
+
+func newChannelDirectory(c *Client, store CacheStore, ttl time.Duration) *ChannelDirectory {
+	ret := &ChannelDirectory{
+		client: c,
+	}
+	ret.directoryCache = directoryCache{
+		store: store,
+		kind:  channelsCacheKind,
+		ttl:   ttl,
+		ctx:   c.ctx,
+		fetch: func(ctx context.Context) (interface{}, error) {
+			list, err := c.ConversationsList(ctx, false, PublicChannel, PrivateChannel)
+			if errors.Is(err, ErrMissingScope) {
+				list, err = c.ConversationsList(ctx, false, PublicChannel)
+			}
+			return &list, err
+		},
+		empty: func() interface{} { return &[]Channel{} },
+		reindex: func(v interface{}) {
+			ret.reset()
+			for _, ch := range *v.(*[]Channel) {
+				ret.put(ch)
+			}
+		},
+		entries: func() interface{} {
+			list := ret.Channels()
+			return &list
+		},
+	}
+	ret.reset()
+	return ret
+}
+
+func (d *ChannelDirectory) reset() {
+	d.byID = map[string]Channel{}
+	d.byName = map[string]string{}
+}
+
+// Refresh replaces the cached channels with all the public and private channels in the Slack team,
+// and saves them to the cache store. Without the scope to read the private channels, only the public ones are cached.
+// required scopes: `channels:read`, and `groups:read` for the private channels
+func (d *ChannelDirectory) Refresh(ctx context.Context) error {
+	return d.refresh(ctx)
+}
+
+// Load replaces the cached channels with the channels saved in the cache store.
+// The loaded channels are refreshed when the TTL has passed since they were saved.
+// It returns ErrCacheMiss if the store has no channels.
+func (d *ChannelDirectory) Load(ctx context.Context) error {
+	return d.load(ctx)
+}
+
+// Save saves the cached channels, including the updates by the events, to the cache store.
+// The cache which has never been loaded or refreshed is not saved.
+func (d *ChannelDirectory) Save(ctx context.Context) error {
+	return d.save(ctx)
+}
+
+// Put adds the channel to the directory or updates it, e.g. by the channel_created event.
+func (d *ChannelDirectory) Put(ch Channel) {
+	if ch.ID == "" {
+		return
+	}
+	defer d.mux.Unlock()
+	d.mux.Lock()
+	d.put(ch)
+}
+
+func (d *ChannelDirectory) put(ch Channel) {
+	if old, ok := d.byID[ch.ID]; ok {
+		unindex(d.byName, old.Name, old.ID)
+	}
+	d.byID[ch.ID] = ch
+	index(d.byName, ch.Name, ch.ID)
+}
+
+// Rename renames the cached channel, e.g. by the channel_rename event.
+func (d *ChannelDirectory) Rename(id, name string) {
+	defer d.mux.Unlock()
+	d.mux.Lock()
+	ch, ok := d.byID[id]
+	if !ok {
+		ch = Channel{ID: id}
+	}
+	if ch.Name != "" && ch.Name != name {
+		ch.PreviousNames = append([]string{ch.Name}, ch.PreviousNames...)
+	}
+	ch.Name = name
+	ch.NameNormalized = name
+	d.put(ch)
+}
+
+// SetArchived marks the cached channel archived or unarchived, e.g. by the channel_archive event.
+func (d *ChannelDirectory) SetArchived(id string, archived bool) {
+	defer d.mux.Unlock()
+	d.mux.Lock()
+	if ch, ok := d.byID[id]; ok {
+		ch.IsArchived = archived
+		d.byID[id] = ch
+	}
+}
+
+// Remove removes the channel from the directory, e.g. by the channel_deleted event.
+func (d *ChannelDirectory) Remove(id string) {
+	defer d.mux.Unlock()
+	d.mux.Lock()
+	if ch, ok := d.byID[id]; ok {
+		unindex(d.byName, ch.Name, ch.ID)
+		delete(d.byID, id)
+	}
+}
+
+// Lookup returns the cached channel of the ID.
+func (d *ChannelDirectory) Lookup(id string) (Channel, bool) {
+	d.refreshIfStale()
+	d.mux.RLock()
+	defer d.mux.RUnlock()
+	ch, ok := d.byID[id]
+	return ch, ok
+}
+
+// Get returns the channel of the ID. If the channel is not cached, Get looks up it by conversations.info and caches it.
+// see. https://api.slack.com/methods/conversations.info
+func (d *ChannelDirectory) Get(ctx context.Context, id string) (Channel, error) {
+	if ch, ok := d.Lookup(id); ok {
+		return ch, nil
+	}
+	ch, err := d.client.ConversationsInfo(ctx, id)
+	if err != nil {
+		return Channel{}, err
+	}
+	if !ch.IsIM && !ch.IsMPIM {
+		d.Put(*ch)
+	}
+	return *ch, nil
+}
+
+// ByName returns the cached channel of the name. The name may start with "#".
+func (d *ChannelDirectory) ByName(name string) (Channel, bool) {
+	name = strings.TrimPrefix(name, "#")
+	d.refreshIfStale()
+	d.mux.RLock()
+	defer d.mux.RUnlock()
+	id, ok := d.byName[name]
+	if !ok || name == "" {
+		return Channel{}, false
+	}
+	ch, ok := d.byID[id]
+	return ch, ok
+}
+
+// Name returns the name of the cached channel of the ID, or the empty string if the channel is not cached.
+func (d *ChannelDirectory) Name(id string) string {
+	ch, _ := d.Lookup(id)
+	return ch.Name
+}
+
+// Resolve returns the ID of the channel. The channel is the ID, or the name which starts with "#".
+// If the name is not cached, Resolve refreshes the cache to find it, unless it has been refreshed recently.
+func (d *ChannelDirectory) Resolve(ctx context.Context, channel string) (string, error) {
+	if !strings.HasPrefix(channel, "#") {
+		return channel, nil
+	}
+	if ch, ok := d.ByName(channel); ok {
+		return ch.ID, nil
+	}
+	if !d.refreshedWithin(missRefreshInterval) {
+		if err := d.Refresh(ctx); err != nil {
+			return "", fmt.Errorf("channel resolve error: %s, %w", channel, err)
+		}
+		if ch, ok := d.ByName(channel); ok {
+			return ch.ID, nil
+		}
+	}
+	return "", fmt.Errorf("channel resolve error: %s, %w", channel, &APIError{
+		Method: conversationsListMethod,
+		Code:   ErrChannelNotFound.Code,
+	})
+}
+
+// Channels returns all the cached channels ordered by the ID.
+func (d *ChannelDirectory) Channels() []Channel {
+	d.mux.RLock()
+	ret := make([]Channel, 0, len(d.byID))
+	for _, v := range d.byID {
+		ret = append(ret, v)
+	}
+	d.mux.RUnlock()
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].ID < ret[j].ID
+	})
+	return ret
+}
+
+// Len returns the number of the cached channels.
+func (d *ChannelDirectory) Len() int {
+	d.mux.RLock()
+	defer d.mux.RUnlock()
+	return len(d.byID)
+}
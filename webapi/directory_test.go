@@ -0,0 +1,292 @@
+package webapi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ikawaha/slackbot/slacktest"
+	"github.com/ikawaha/slackbot/webapi"
+)
+
+// eventually waits for the condition to hold, e.g. after a background refresh.
+func eventually(t *testing.T, cond func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(5 * time.Second); !cond(); {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not satisfied in time")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// methodCounter counts the calls of each Web API method.
+type methodCounter struct {
+	mux    sync.Mutex
+	counts map[string]int
+}
+
+func (m *methodCounter) option() webapi.Option {
+	return webapi.HTTPClient(&http.Client{
+		Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			m.mux.Lock()
+			if m.counts == nil {
+				m.counts = map[string]int{}
+			}
+			m.counts[path.Base(r.URL.Path)]++
+			m.mux.Unlock()
+			return http.DefaultTransport.RoundTrip(r)
+		}),
+	})
+}
+
+func (m *methodCounter) count(method string) int {
+	defer m.mux.Unlock()
+	m.mux.Lock()
+	return m.counts[method]
+}
+
+func TestUserDirectory_Index(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	srv.AddUser(webapi.User{ID: "U1", Name: "alice", Profile: webapi.UserProfile{DisplayName: "Alice", Email: "Alice@example.com"}})
+	srv.AddUser(webapi.User{ID: "U2", Name: "bob", Profile: webapi.UserProfile{DisplayName: "Bobby", Email: "bob@example.com"}})
+	srv.AddUser(webapi.User{ID: "U3", Name: "carol", Deleted: true, Profile: webapi.UserProfile{DisplayName: "Carol"}})
+	c := newClient(t, srv, webapi.CacheUsers())
+	defer c.Close()
+	d := c.UserDirectory()
+
+	tests := []struct {
+		name   string
+		lookup func() (webapi.User, bool)
+		want   string
+	}{
+		{name: "id", lookup: func() (webapi.User, bool) { return d.Lookup("U1") }, want: "U1"},
+		{name: "name", lookup: func() (webapi.User, bool) { return d.ByName("bob") }, want: "U2"},
+		{name: "display name", lookup: func() (webapi.User, bool) { return d.ByDisplayName("Alice") }, want: "U1"},
+		{name: "email", lookup: func() (webapi.User, bool) { return d.ByEmail("alice@EXAMPLE.com") }, want: "U1"},
+		{name: "deleted user by id", lookup: func() (webapi.User, bool) { return d.Lookup("U3") }, want: "U3"},
+		{name: "deleted user by name", lookup: func() (webapi.User, bool) { return d.ByName("carol") }},
+		{name: "deleted user by display name", lookup: func() (webapi.User, bool) { return d.ByDisplayName("Carol") }},
+		{name: "unknown name", lookup: func() (webapi.User, bool) { return d.ByName("dave") }},
+		{name: "empty email", lookup: func() (webapi.User, bool) { return d.ByEmail("") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, ok := tt.lookup()
+			if ok != (tt.want != "") || u.ID != tt.want {
+				t.Errorf("want %q, got %+v, %v", tt.want, u, ok)
+			}
+		})
+	}
+	if d.Len() != 3 || len(d.Users()) != 3 || d.Users()[0].ID != "U1" {
+		t.Errorf("want 3 users ordered by the ID, got %+v", d.Users())
+	}
+
+	// Put replaces the indexes of the updated user.
+	d.Put(webapi.User{ID: "U2", Name: "robert", Profile: webapi.UserProfile{DisplayName: "Rob", Email: "rob@example.com"}})
+	if _, ok := d.ByName("bob"); ok {
+		t.Error("want the old name to be unindexed")
+	}
+	if _, ok := d.ByEmail("bob@example.com"); ok {
+		t.Error("want the old email to be unindexed")
+	}
+	if u, ok := d.ByDisplayName("Rob"); !ok || u.Name != "robert" {
+		t.Errorf("want robert, got %+v, %v", u, ok)
+	}
+	d.Put(webapi.User{Name: "no id"})
+	if d.Len() != 3 {
+		t.Errorf("want the user without the ID to be ignored, got %d users", d.Len())
+	}
+}
+
+func TestUserDirectory_Get(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	srv.AddUser(webapi.User{ID: "U1", Name: "alice"})
+	c := newClient(t, srv, webapi.CacheUsers())
+	defer c.Close()
+	d := c.UserDirectory()
+
+	// the user joined after the cache was filled is looked up by users.info.
+	srv.AddUser(webapi.User{ID: "U2", Name: "bob"})
+	if _, ok := d.Lookup("U2"); ok {
+		t.Fatal("want U2 not to be cached yet")
+	}
+	u, err := d.Get(context.Background(), "U2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if u.Name != "bob" {
+		t.Errorf("want bob, got %+v", u)
+	}
+	if u, ok := d.ByName("bob"); !ok || u.ID != "U2" {
+		t.Errorf("want bob to be cached, got %+v, %v", u, ok)
+	}
+	if _, err := d.Get(context.Background(), "U9"); !errors.Is(err, webapi.ErrUserNotFound) {
+		t.Errorf("want ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestUserDirectory_TTL(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	srv.AddUser(webapi.User{ID: "U1", Name: "alice"})
+	c := newClient(t, srv, webapi.CacheUsers(), webapi.UsersCacheTTL(10*time.Millisecond))
+	defer c.Close()
+	d := c.UserDirectory()
+
+	srv.AddUser(webapi.User{ID: "U2", Name: "bob"})
+	time.Sleep(20 * time.Millisecond)
+	if d.Len() != 1 {
+		t.Fatalf("want no refresh without lookups, got %d users", d.Len())
+	}
+	// the expired cache is served while it is refreshed in the background.
+	if _, ok := d.ByName("bob"); ok {
+		t.Error("want the stale cache to be served")
+	}
+	eventually(t, func() bool { return d.Len() == 2 })
+}
+
+func TestClient_Close(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	srv.AddUser(webapi.User{ID: "U1", Name: "alice"})
+	c := newClient(t, srv, webapi.CacheUsers(), webapi.UsersCacheTTL(time.Millisecond))
+
+	var (
+		lists    int32
+		canceled = make(chan struct{})
+	)
+	srv.HandleMethod("users.list", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&lists, 1)
+		_ = r.ParseForm() // the server notices the canceled request after reading the body.
+		<-r.Context().Done()
+		close(canceled)
+	})
+	time.Sleep(5 * time.Millisecond)
+	c.UserDirectory().Lookup("U1")
+	eventually(t, func() bool { return atomic.LoadInt32(&lists) == 1 })
+
+	// Close cancels the background refresh and waits for it.
+	if err := c.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	select {
+	case <-canceled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the background refresh was not canceled")
+	}
+	// no refresh starts after closing.
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := c.UserDirectory().Lookup("U1"); !ok {
+		t.Error("want the cache to be served after closing")
+	}
+	time.Sleep(20 * time.Millisecond)
+	if got := atomic.LoadInt32(&lists); got != 1 {
+		t.Errorf("want no refresh after closing, got %d", got)
+	}
+}
+
+func TestChannelDirectory_Resolve(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	srv.AddChannel(webapi.Channel{ID: "C1", Name: "general", IsChannel: true})
+	var calls methodCounter
+	c := newClient(t, srv, calls.option())
+	defer c.Close()
+	d := c.ChannelDirectory()
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		channel string
+		want    string
+		lists   int // the calls of conversations.list so far
+		wantErr error
+		before  func()
+	}{
+		{name: "raw id passes through", channel: "C9", want: "C9", lists: 0},
+		{name: "miss refreshes the cache", channel: "#general", want: "C1", lists: 1},
+		{name: "hit", channel: "#general", want: "C1", lists: 1},
+		{
+			name:    "miss within the guard refreshes nothing",
+			channel: "#random",
+			lists:   1,
+			wantErr: webapi.ErrChannelNotFound,
+			// the channel created after the refresh is not found until the next refresh.
+			before: func() { srv.AddChannel(webapi.Channel{ID: "C2", Name: "random", IsChannel: true}) },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.before != nil {
+				tt.before()
+			}
+			got, err := d.Resolve(ctx, tt.channel)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("want %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("want %q, got %q", tt.want, got)
+			}
+			if n := calls.count("conversations.list"); n != tt.lists {
+				t.Errorf("want %d calls of conversations.list, got %d", tt.lists, n)
+			}
+		})
+	}
+
+	// the channel_created event puts the channel to the cache.
+	d.Put(webapi.Channel{ID: "C2", Name: "random"})
+	if got, err := d.Resolve(ctx, "#random"); err != nil || got != "C2" {
+		t.Errorf("want C2, got %q, %v", got, err)
+	}
+	// the renamed channel is found by the new name only.
+	d.Rename("C1", "town")
+	if _, err := d.Resolve(ctx, "#general"); !errors.Is(err, webapi.ErrChannelNotFound) {
+		t.Errorf("want ErrChannelNotFound by the old name, got %v", err)
+	}
+	if ch, ok := d.ByName("town"); !ok || ch.ID != "C1" || len(ch.PreviousNames) != 1 || ch.PreviousNames[0] != "general" {
+		t.Errorf("want C1 renamed from general, got %+v, %v", ch, ok)
+	}
+	d.Remove("C2")
+	if _, ok := d.Lookup("C2"); ok {
+		t.Error("want C2 to be removed")
+	}
+}
+
+func TestClient_ChannelName(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	srv.AddChannel(webapi.Channel{ID: "C1", Name: "general", IsChannel: true}, "U1", "U2")
+	c := newClient(t, srv)
+	defer c.Close()
+	ctx := context.Background()
+
+	// the conversations methods take the name as the chat methods do.
+	ch, err := c.ConversationsInfo(ctx, "#general")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ch.ID != "C1" {
+		t.Errorf("want C1, got %+v", ch)
+	}
+	members, err := c.ConversationsMembers(ctx, "#general")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(members) != 2 {
+		t.Errorf("want 2 members, got %v", members)
+	}
+	if _, err := c.ConversationsInfo(ctx, "#nowhere"); !errors.Is(err, webapi.ErrChannelNotFound) {
+		t.Errorf("want ErrChannelNotFound, got %v", err)
+	}
+	if _, err := c.PostMessage(ctx, "#nowhere", "hello"); !errors.Is(err, webapi.ErrChannelNotFound) {
+		t.Errorf("want ErrChannelNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,36 @@
+package webapi
+
+import (
+	"github.com/ikawaha/slackbot/internal/slackapi"
+)
+
+// Sentinel values of the common error codes, to be matched by errors.Is.
+// see. https://api.slack.com/web#errors
+var (
+	ErrMissingScope       = &APIError{Code: "missing_scope"}
+	ErrNotAuthed          = &APIError{Code: "not_authed"}
+	ErrInvalidAuth        = &APIError{Code: "invalid_auth"}
+	ErrAccountInactive    = &APIError{Code: "account_inactive"}
+	ErrTokenRevoked       = &APIError{Code: "token_revoked"}
+	ErrRateLimited        = &APIError{Code: slackapi.CodeRateLimited}
+	ErrChannelNotFound    = &APIError{Code: "channel_not_found"}
+	ErrNotInChannel       = &APIError{Code: "not_in_channel"}
+	ErrIsArchived         = &APIError{Code: "is_archived"}
+	ErrUserNotFound       = &APIError{Code: "user_not_found"}
+	ErrMessageNotFound    = &APIError{Code: "message_not_found"}
+	ErrInvalidBlocks      = &APIError{Code: "invalid_blocks"}
+	ErrFatalError         = &APIError{Code: "fatal_error"}
+	ErrInternalError      = &APIError{Code: "internal_error"}
+	ErrServiceUnavailable = &APIError{Code: "service_unavailable"}
+
+	// ErrUnknownError is the code of the APIError of the response which is not ok without an error code.
+	ErrUnknownError = &APIError{Code: slackapi.CodeUnknownError}
+)
+
+// ResponseMetadata is the metadata of the Web API response.
+type ResponseMetadata = slackapi.ResponseMetadata
+
+// APIError represents the error of the Slack Web API call.
+// It has the method, the error code and the HTTP status code of the response, and is matched
+// with the sentinel values by errors.Is.
+type APIError = slackapi.APIError
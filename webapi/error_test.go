@@ -0,0 +1,58 @@
+package webapi_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/ikawaha/slackbot/slacktest"
+	"github.com/ikawaha/slackbot/webapi"
+)
+
+func TestClient_APIError(t *testing.T) {
+	ctx := context.Background()
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	c := newClient(t, srv)
+
+	srv.SetError("conversations.info", "channel_not_found")
+	_, err := c.ConversationsInfo(ctx, "C1")
+	if !errors.Is(err, webapi.ErrChannelNotFound) {
+		t.Errorf("want ErrChannelNotFound, got %v", err)
+	}
+	if !errors.Is(err, &webapi.APIError{Method: "conversations.info", Code: "channel_not_found"}) {
+		t.Errorf("want the error of conversations.info, got %v", err)
+	}
+	var apiErr *webapi.APIError
+	if !errors.As(err, &apiErr) || apiErr.Method != "conversations.info" || apiErr.StatusCode != http.StatusOK {
+		t.Errorf("want APIError of conversations.info, got %#v", err)
+	}
+
+	srv.SetError("conversations.info", "")
+	srv.HandleMethod("conversations.info", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"ok":false}`)
+	})
+	if _, err := c.ConversationsInfo(ctx, "C1"); !errors.Is(err, webapi.ErrUnknownError) {
+		t.Errorf("want ErrUnknownError, got %v", err)
+	}
+}
+
+func TestClient_APIError_NotAuthed(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	c, err := webapi.New("", webapi.BaseURL(srv.URL()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := c.UsersInfo(context.Background(), "U1"); err == nil {
+		t.Error("want error without token")
+	}
+	c = newClient(t, srv)
+	srv.SetError("users.info", "invalid_auth")
+	if _, err := c.UsersInfo(context.Background(), "U1"); !errors.Is(err, webapi.ErrInvalidAuth) {
+		t.Errorf("want ErrInvalidAuth, got %v", err)
+	}
+}
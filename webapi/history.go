@@ -0,0 +1,241 @@
+package webapi
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	conversationsHistoryMethod = "conversations.history"
+	conversationsRepliesMethod = "conversations.replies"
+)
+
+// ConversationsHistoryResponse is the response of the conversations.history and conversations.replies API.
+type ConversationsHistoryResponse struct {
+	OK       bool      `json:"ok"`
+	Error    string    `json:"error"`
+	Needed   string    `json:"needed"`
+	Provided string    `json:"provided"`
+	Messages []Message `json:"messages"`
+	HasMore  bool      `json:"has_more"`
+}
+
+// HistoryOption represents the option of the conversations.history and conversations.replies API.
+type HistoryOption func(*historyParams) error
+
+type historyParams struct {
+	oldest             string
+	latest             string
+	inclusive          bool
+	limit              int
+	includeAllMetadata bool
+}
+
+// Oldest retrieves only the messages after the timestamp, e.g. the timestamp of the last message the bot read.
+func Oldest(ts string) HistoryOption {
+	return func(p *historyParams) error {
+		p.oldest = ts
+		return nil
+	}
+}
+
+// Latest retrieves only the messages before the timestamp.
+func Latest(ts string) HistoryOption {
+	return func(p *historyParams) error {
+		p.latest = ts
+		return nil
+	}
+}
+
+// Since retrieves only the messages after the time.
+func Since(t time.Time) HistoryOption {
+	return Oldest(Timestamp(t))
+}
+
+// Until retrieves only the messages before the time.
+func Until(t time.Time) HistoryOption {
+	return Latest(Timestamp(t))
+}
+
+// Inclusive includes the messages of the oldest and the latest timestamps.
+func Inclusive() HistoryOption {
+	return func(p *historyParams) error {
+		p.inclusive = true
+		return nil
+	}
+}
+
+// HistoryLimit sets the number of messages to retrieve in a page, up to MaxPageLimit.
+// The default is the page limit of the client.
+func HistoryLimit(n int) HistoryOption {
+	return func(p *historyParams) error {
+		if n <= 0 || n > MaxPageLimit {
+			return fmt.Errorf("history limit must be between 1 and %d: %d", MaxPageLimit, n)
+		}
+		p.limit = n
+		return nil
+	}
+}
+
+// IncludeAllMetadata retrieves the metadata of the messages.
+func IncludeAllMetadata() HistoryOption {
+	return func(p *historyParams) error {
+		p.includeAllMetadata = true
+		return nil
+	}
+}
+
+// Timestamp returns the message timestamp of the time.
+func Timestamp(t time.Time) string {
+	return fmt.Sprintf("%d.%06d", t.Unix(), t.Nanosecond()/int(time.Microsecond))
+}
+
+// ParseTimestamp returns the time of the message timestamp.
+func ParseTimestamp(ts string) (time.Time, error) {
+	sec, frac := ts, "0"
+	if i := strings.Index(ts, "."); i >= 0 {
+		sec, frac = ts[:i], (ts[i+1:] + "000000")[:6]
+	}
+	s, err := strconv.ParseInt(sec, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp: %q, %w", ts, err)
+	}
+	us, err := strconv.ParseInt(frac, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp: %q, %w", ts, err)
+	}
+	return time.Unix(s, us*int64(time.Microsecond)), nil
+}
+
+// MessageIterator iterates over the messages of a conversation or a thread, following the cursor over the pages.
+//
+//	it := c.NewHistoryIterator(channelID, Since(lastRead))
+//	for it.Next(ctx) {
+//		m := it.Message()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type MessageIterator struct {
+	client  *Client
+	method  string
+	channel string
+	params  url.Values
+	limit   int
+	pages   *Paginator
+	buf     []Message
+	current Message
+	err     error
+}
+
+// NewHistoryIterator creates the iterator over the messages of the conversation, from the newest to the oldest.
+// The channel is the ID, or the name which starts with "#". The replies in threads are not included.
+// required scopes: `channels:history`, `groups:history`, `im:history` or `mpim:history`
+// see. https://api.slack.com/methods/conversations.history
+func (c *Client) NewHistoryIterator(channel string, opts ...HistoryOption) *MessageIterator {
+	return c.newMessageIterator(conversationsHistoryMethod, channel, url.Values{}, opts)
+}
+
+// NewRepliesIterator creates the iterator over the messages of the thread, from the parent message to the latest reply.
+// The channel is the ID, or the name which starts with "#".
+// see. https://api.slack.com/methods/conversations.replies
+func (c *Client) NewRepliesIterator(channel, threadTS string, opts ...HistoryOption) *MessageIterator {
+	return c.newMessageIterator(conversationsRepliesMethod, channel, url.Values{"ts": {threadTS}}, opts)
+}
+
+func (c *Client) newMessageIterator(method, channel string, params url.Values, opts []HistoryOption) *MessageIterator {
+	ret := &MessageIterator{
+		client:  c,
+		method:  method,
+		channel: channel,
+		params:  params,
+	}
+	var p historyParams
+	for _, opt := range opts {
+		if err := opt(&p); err != nil {
+			ret.err = err
+			return ret
+		}
+	}
+	if p.oldest != "" {
+		params.Set("oldest", p.oldest)
+	}
+	if p.latest != "" {
+		params.Set("latest", p.latest)
+	}
+	if p.inclusive {
+		params.Set("inclusive", "true")
+	}
+	if p.includeAllMetadata {
+		params.Set("include_all_metadata", "true")
+	}
+	ret.limit = p.limit
+	return ret
+}
+
+// Next advances the iterator to the next message, fetching the next page if needed.
+// It returns false when there are no more messages or an error occurred.
+func (it *MessageIterator) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+	if it.pages == nil {
+		channelID, err := it.client.channels.Resolve(ctx, it.channel)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.params.Set("channel", channelID)
+		it.pages = it.client.NewPaginator(it.method, it.params, it.limit)
+	}
+	for len(it.buf) == 0 {
+		var page ConversationsHistoryResponse
+		if !it.pages.Next(ctx, &page) {
+			it.err = it.pages.Err()
+			return false
+		}
+		it.buf = page.Messages
+	}
+	it.current, it.buf = it.buf[0], it.buf[1:]
+	return true
+}
+
+// Message returns the current message.
+func (it *MessageIterator) Message() Message {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration.
+func (it *MessageIterator) Err() error {
+	return it.err
+}
+
+// ConversationsHistory retrieves all the messages of the conversation in the range of the options,
+// from the newest to the oldest.
+// see. https://api.slack.com/methods/conversations.history
+func (c *Client) ConversationsHistory(ctx context.Context, channel string, opts ...HistoryOption) ([]Message, error) {
+	return collectMessages(ctx, c.NewHistoryIterator(channel, opts...))
+}
+
+// ConversationsReplies retrieves all the messages of the thread in the range of the options,
+// from the parent message to the latest reply.
+// see. https://api.slack.com/methods/conversations.replies
+func (c *Client) ConversationsReplies(ctx context.Context, channel, threadTS string, opts ...HistoryOption) ([]Message, error) {
+	return collectMessages(ctx, c.NewRepliesIterator(channel, threadTS, opts...))
+}
+
+func collectMessages(ctx context.Context, it *MessageIterator) ([]Message, error) {
+	var ret []Message
+	for it.Next(ctx) {
+		ret = append(ret, it.Message())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
@@ -0,0 +1,119 @@
+package webapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ikawaha/slackbot/blocks"
+	"github.com/ikawaha/slackbot/slacktest"
+	"github.com/ikawaha/slackbot/webapi"
+)
+
+func TestClient_ConversationsHistory(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	srv.AddChannel(webapi.Channel{ID: "C1", Name: "general"})
+	rich := json.RawMessage(`[{"type":"rich_text","block_id":"x","elements":[]},{"type":"section","text":{"type":"mrkdwn","text":"hi"}}]`)
+	for i := 1; i <= 5; i++ {
+		srv.AddMessage(slacktest.PostedMessage{Channel: "C1", User: "U1", Text: "m", TS: webapi.Timestamp(time.Unix(int64(1000+i), 0)), Blocks: rich})
+	}
+	srv.AddMessage(slacktest.PostedMessage{Channel: "C1", User: "U1", Text: "parent", TS: "2000.000001"})
+	srv.AddMessage(slacktest.PostedMessage{Channel: "C1", User: "U2", Text: "r1", TS: "2000.000002", ThreadTS: "2000.000001"})
+	srv.AddMessage(slacktest.PostedMessage{Channel: "C1", User: "U3", Text: "r2", TS: "2000.000003", ThreadTS: "2000.000001"})
+	c := newClient(t, srv)
+	defer c.Close()
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		opts []webapi.HistoryOption
+		want []string // the timestamps of the messages, the newest first
+	}{
+		{name: "all pages", opts: []webapi.HistoryOption{webapi.HistoryLimit(2)}, want: []string{"2000.000001", "1005.000000", "1004.000000", "1003.000000", "1002.000000", "1001.000000"}},
+		{name: "oldest exclusive", opts: []webapi.HistoryOption{webapi.Oldest("1004.000000")}, want: []string{"2000.000001", "1005.000000"}},
+		{name: "oldest inclusive", opts: []webapi.HistoryOption{webapi.Oldest("1004.000000"), webapi.Inclusive()}, want: []string{"2000.000001", "1005.000000", "1004.000000"}},
+		{name: "since and until", opts: []webapi.HistoryOption{webapi.Since(time.Unix(1001, 0)), webapi.Until(time.Unix(1003, 0))}, want: []string{"1002.000000"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ms, err := c.ConversationsHistory(ctx, "#general", tt.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var got []string
+			for _, m := range ms {
+				got = append(got, m.TS)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("want %v, got %v", tt.want, got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("want %v, got %v", tt.want, got)
+					break
+				}
+			}
+		})
+	}
+
+	ms, err := c.ConversationsHistory(ctx, "C1", webapi.Oldest("1004.000000"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p := ms[0]; p.ReplyCount != 2 || !p.IsThreadParent() {
+		t.Errorf("want the thread parent with 2 replies, got %+v", p)
+	}
+	if bs := ms[1].Blocks; len(bs) != 2 || bs[0].BlockType() != "rich_text" {
+		t.Errorf("want the blocks of the message, got %+v", bs)
+	} else if _, ok := bs[1].(*blocks.Section); !ok {
+		t.Errorf("want a section, got %T", bs[1])
+	}
+
+	replies, err := c.ConversationsReplies(ctx, "C1", "2000.000001", webapi.Oldest("2000.000002"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(replies) != 2 || replies[0].Text != "parent" || replies[1].Text != "r2" {
+		t.Errorf("want the parent and r2, got %+v", replies)
+	}
+	if _, err := c.ConversationsReplies(ctx, "C1", "9.000001"); !errors.Is(err, &webapi.APIError{Code: "thread_not_found"}) {
+		t.Errorf("want thread_not_found, got %v", err)
+	}
+
+	it := c.NewHistoryIterator("#nowhere")
+	for it.Next(ctx) {
+		t.Errorf("unexpected message: %+v", it.Message())
+	}
+	if !errors.Is(it.Err(), webapi.ErrChannelNotFound) {
+		t.Errorf("want ErrChannelNotFound, got %v", it.Err())
+	}
+}
+
+func TestTimestamp(t *testing.T) {
+	tests := []struct {
+		ts      string
+		want    time.Time
+		wantErr bool
+	}{
+		{ts: "1700000000.000100", want: time.Unix(1700000000, 100000)},
+		{ts: "1700000000", want: time.Unix(1700000000, 0)},
+		{ts: "abc.000001", wantErr: true},
+		{ts: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := webapi.ParseTimestamp(tt.ts)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%q: unexpected error: %v", tt.ts, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("%q: want %v, got %v", tt.ts, tt.want, got)
+		}
+	}
+	if got := webapi.Timestamp(time.Unix(1700000000, 100000)); got != "1700000000.000100" {
+		t.Errorf("want 1700000000.000100, got %s", got)
+	}
+}
@@ -1,5 +1,12 @@
 package webapi
 
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ikawaha/slackbot/blocks"
+)
+
 // MessageResponse represents the response of the chat.postMessage API.
 type MessageResponse struct {
 	OK       bool    `json:"ok,omitempty"`
@@ -12,19 +19,234 @@ type MessageResponse struct {
 }
 
 // Message represents the Slack message.
+// see. https://api.slack.com/events/message
 type Message struct {
-	Text        string       `json:"text,omitempty"`
-	Username    string       `json:"username,omitempty"`
-	BotID       string       `json:"bot_id,omitempty"`
-	Attachments []Attachment `json:"attachments,omitempty"`
-	Type        string       `json:"type,omitempty"`
-	SubType     string       `json:"sub_type,omitempty"`
-	TS          string       `json:"ts,omitempty"`
+	Type        string        `json:"type,omitempty"`
+	SubType     string        `json:"subtype,omitempty"`
+	User        string        `json:"user,omitempty"`
+	BotID       string        `json:"bot_id,omitempty"`
+	Username    string        `json:"username,omitempty"`
+	Team        string        `json:"team,omitempty"`
+	Text        string        `json:"text,omitempty"`
+	Blocks      blocks.Blocks `json:"blocks,omitempty"`
+	Attachments []Attachment  `json:"attachments,omitempty"`
+	Files       []File        `json:"files,omitempty"`
+	Reactions   []Reaction    `json:"reactions,omitempty"`
+	TS          string        `json:"ts,omitempty"`
+	ClientMsgID string        `json:"client_msg_id,omitempty"`
+	Edited      *Edited       `json:"edited,omitempty"`
+	Metadata    *Metadata     `json:"metadata,omitempty"`
+
+	// for messages in a thread
+	ThreadTS        string   `json:"thread_ts,omitempty"`
+	ParentUserID    string   `json:"parent_user_id,omitempty"`
+	ReplyCount      int      `json:"reply_count,omitempty"`
+	ReplyUsersCount int      `json:"reply_users_count,omitempty"`
+	ReplyUsers      []string `json:"reply_users,omitempty"`
+	LatestReply     string   `json:"latest_reply,omitempty"`
+}
+
+// IsThreadParent returns true, if the message is the parent message of a thread.
+func (m Message) IsThreadParent() bool {
+	return m.ThreadTS != "" && m.ThreadTS == m.TS
+}
+
+// Edited represents who and when edited the message.
+type Edited struct {
+	User string `json:"user"`
+	TS   string `json:"ts"`
+}
+
+// Reaction represents the emoji reaction to the message.
+type Reaction struct {
+	Name  string   `json:"name"`
+	Count int      `json:"count"`
+	Users []string `json:"users"`
+}
+
+// File represents the file shared in the message.
+// see. https://api.slack.com/types/file
+type File struct {
+	ID                 string `json:"id"`
+	Name               string `json:"name,omitempty"`
+	Title              string `json:"title,omitempty"`
+	Mimetype           string `json:"mimetype,omitempty"`
+	Filetype           string `json:"filetype,omitempty"`
+	PrettyType         string `json:"pretty_type,omitempty"`
+	User               string `json:"user,omitempty"`
+	Size               int    `json:"size,omitempty"`
+	Mode               string `json:"mode,omitempty"`
+	IsExternal         bool   `json:"is_external,omitempty"`
+	IsPublic           bool   `json:"is_public,omitempty"`
+	URLPrivate         string `json:"url_private,omitempty"`
+	URLPrivateDownload string `json:"url_private_download,omitempty"`
+	Permalink          string `json:"permalink,omitempty"`
+	Thumb360           string `json:"thumb_360,omitempty"`
+	Created            int64  `json:"created,omitempty"` // unix time
 }
 
 // Attachment is a part of the Message.
+// see. https://api.slack.com/reference/messaging/attachments
 type Attachment struct {
-	Text     string `json:"text,omitempty"`
-	ID       int    `json:"id,omitempty"`
-	Fallback string `json:"fallback,omitempty"`
+	Text       string            `json:"text,omitempty"`
+	ID         int               `json:"id,omitempty"`
+	Fallback   string            `json:"fallback,omitempty"`
+	Color      string            `json:"color,omitempty"`
+	Pretext    string            `json:"pretext,omitempty"`
+	AuthorName string            `json:"author_name,omitempty"`
+	AuthorLink string            `json:"author_link,omitempty"`
+	AuthorIcon string            `json:"author_icon,omitempty"`
+	Title      string            `json:"title,omitempty"`
+	TitleLink  string            `json:"title_link,omitempty"`
+	Fields     []AttachmentField `json:"fields,omitempty"`
+	ImageURL   string            `json:"image_url,omitempty"`
+	ThumbURL   string            `json:"thumb_url,omitempty"`
+	Footer     string            `json:"footer,omitempty"`
+	FooterIcon string            `json:"footer_icon,omitempty"`
+	TS         int64             `json:"ts,omitempty"`
+	MarkdownIn []string          `json:"mrkdwn_in,omitempty"`
+}
+
+// AttachmentField is a field displayed in a table inside the attachment.
+type AttachmentField struct {
+	Title string `json:"title,omitempty"`
+	Value string `json:"value,omitempty"`
+	Short bool   `json:"short,omitempty"`
+}
+
+// Metadata is the message metadata.
+// see. https://api.slack.com/metadata
+type Metadata struct {
+	EventType    string                 `json:"event_type"`
+	EventPayload map[string]interface{} `json:"event_payload"`
+}
+
+// MessageOption represents the option of the message.
+type MessageOption func(*messageParams) error
+
+// messageParams is the request body of the message APIs.
+type messageParams struct {
+	Channel        string         `json:"channel,omitempty"`
+	TS             string         `json:"ts,omitempty"`      // for chat.update
+	User           string         `json:"user,omitempty"`    // for chat.postEphemeral
+	PostAt         int64          `json:"post_at,omitempty"` // for chat.scheduleMessage
+	Text           string         `json:"text,omitempty"`
+	Blocks         []blocks.Block `json:"blocks,omitempty"`
+	Attachments    []Attachment   `json:"attachments,omitempty"`
+	ThreadTS       string         `json:"thread_ts,omitempty"`
+	ReplyBroadcast bool           `json:"reply_broadcast,omitempty"`
+	UnfurlLinks    *bool          `json:"unfurl_links,omitempty"`
+	UnfurlMedia    *bool          `json:"unfurl_media,omitempty"`
+	Mrkdwn         *bool          `json:"mrkdwn,omitempty"`
+	Username       string         `json:"username,omitempty"`
+	IconEmoji      string         `json:"icon_emoji,omitempty"`
+	IconURL        string         `json:"icon_url,omitempty"`
+	Metadata       *Metadata      `json:"metadata,omitempty"`
+	ResponseType   string         `json:"response_type,omitempty"` // for command responses
+}
+
+// Blocks sets the Block Kit blocks of the message.
+// The text of the message is used as the fallback for notifications.
+func Blocks(bs ...blocks.Block) MessageOption {
+	return func(p *messageParams) error {
+		all := append(append([]blocks.Block{}, p.Blocks...), bs...)
+		if err := blocks.Blocks(all).Validate(); err != nil {
+			return fmt.Errorf("invalid blocks: %w", err)
+		}
+		p.Blocks = all
+		return nil
+	}
+}
+
+// Attachments sets the legacy secondary attachments of the message.
+func Attachments(as ...Attachment) MessageOption {
+	return func(p *messageParams) error {
+		p.Attachments = append(p.Attachments, as...)
+		return nil
+	}
+}
+
+// ThreadTS posts the message as a reply to the thread of the parent message timestamp.
+func ThreadTS(ts string) MessageOption {
+	return func(p *messageParams) error {
+		p.ThreadTS = ts
+		return nil
+	}
+}
+
+// ReplyBroadcast makes the thread reply visible to everyone in the channel.
+// It is used in conjunction with ThreadTS.
+func ReplyBroadcast() MessageOption {
+	return func(p *messageParams) error {
+		p.ReplyBroadcast = true
+		return nil
+	}
+}
+
+// UnfurlLinks enables or disables unfurling of primarily text-based content.
+func UnfurlLinks(enable bool) MessageOption {
+	return func(p *messageParams) error {
+		p.UnfurlLinks = &enable
+		return nil
+	}
+}
+
+// UnfurlMedia enables or disables unfurling of media content.
+func UnfurlMedia(enable bool) MessageOption {
+	return func(p *messageParams) error {
+		p.UnfurlMedia = &enable
+		return nil
+	}
+}
+
+// Mrkdwn enables or disables the Slack markup parsing of the message text.
+func Mrkdwn(enable bool) MessageOption {
+	return func(p *messageParams) error {
+		p.Mrkdwn = &enable
+		return nil
+	}
+}
+
+// Username sets the bot's user name of the message.
+// required scopes: `chat:write.customize`
+func Username(name string) MessageOption {
+	return func(p *messageParams) error {
+		p.Username = name
+		return nil
+	}
+}
+
+// IconEmoji sets the emoji to use as the icon of the message, e.g. ":chart_with_upwards_trend:".
+// required scopes: `chat:write.customize`
+func IconEmoji(emoji string) MessageOption {
+	return func(p *messageParams) error {
+		if p.IconURL != "" {
+			return errors.New("icon_emoji and icon_url are exclusive")
+		}
+		p.IconEmoji = emoji
+		return nil
+	}
+}
+
+// IconURL sets the URL to an image to use as the icon of the message.
+// required scopes: `chat:write.customize`
+func IconURL(u string) MessageOption {
+	return func(p *messageParams) error {
+		if p.IconEmoji != "" {
+			return errors.New("icon_emoji and icon_url are exclusive")
+		}
+		p.IconURL = u
+		return nil
+	}
+}
+
+// WithMetadata sets the metadata of the message.
+func WithMetadata(eventType string, payload map[string]interface{}) MessageOption {
+	return func(p *messageParams) error {
+		if eventType == "" {
+			return errors.New("metadata event type is empty")
+		}
+		p.Metadata = &Metadata{EventType: eventType, EventPayload: payload}
+		return nil
+	}
 }
@@ -1,17 +1,78 @@
 package webapi
 
 import (
-	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ikawaha/slackbot/internal/slackapi"
 )
 
 // Option represents the client's option.
 type Option func(*Client) error
 
 // CacheUsers lists all users in a Slack team and caches it.
+// If the cache store has the users saved, the client warm-starts from them instead,
+// and refreshes them in the background.
 // required scopes: `users:read`
 func CacheUsers() Option {
 	return func(c *Client) error {
-		return c.RefreshUsersCache(context.TODO())
+		c.cacheUsers = true
+		return nil
+	}
+}
+
+// CacheChannels lists all the public and private channels in a Slack team and caches them.
+// If the cache store has the channels saved, the client warm-starts from them instead,
+// and refreshes them in the background.
+// Without it, the channels are cached on the first lookup by the name.
+// required scopes: `channels:read`, and `groups:read` for the private channels
+func CacheChannels() Option {
+	return func(c *Client) error {
+		c.cacheChannels = true
+		return nil
+	}
+}
+
+// ChannelsCacheTTL sets the time to live of the channel cache.
+// The cache older than the TTL is refreshed in the background on the next lookup;
+// no timer refreshes it without lookups.
+func ChannelsCacheTTL(d time.Duration) Option {
+	return func(c *Client) error {
+		if d < 0 {
+			return fmt.Errorf("channels cache ttl must not be negative: %v", d)
+		}
+		c.channelsTTL = d
+		return nil
+	}
+}
+
+// SetCacheStore sets the store to persist the caches of the client, e.g. a FileStore to warm-start
+// from the cache saved by the previous run. The default is the MemoryStore.
+// The loaded caches are refreshed in the background after the warm start, and then
+// when the TTL set by UsersCacheTTL or ChannelsCacheTTL passes.
+// The refresh is a full one which lists all the entries again, since Slack has no API to list
+// the changes since the cache was saved. The events such as user_change update the cache in between.
+func SetCacheStore(s CacheStore) Option {
+	return func(c *Client) error {
+		if s == nil {
+			return fmt.Errorf("cache store is nil")
+		}
+		c.cacheStore = s
+		return nil
+	}
+}
+
+// UsersCacheTTL sets the time to live of the user cache.
+// The cache older than the TTL is refreshed in the background on the next lookup;
+// no timer refreshes it without lookups.
+func UsersCacheTTL(d time.Duration) Option {
+	return func(c *Client) error {
+		if d < 0 {
+			return fmt.Errorf("users cache ttl must not be negative: %v", d)
+		}
+		c.usersTTL = d
+		return nil
 	}
 }
 
@@ -22,3 +83,106 @@ func Debug() Option {
 		return nil
 	}
 }
+
+// BaseURL sets the base URL of the Web API, e.g. the URL of a fake server for testing or of a gateway.
+// The URL of each method is the base URL followed by the method name.
+func BaseURL(u string) Option {
+	return func(c *Client) error {
+		v, err := slackapi.NormalizeBaseURL(u)
+		if err != nil {
+			return err
+		}
+		c.baseURL = v
+		return nil
+	}
+}
+
+// HTTPClient sets the HTTP client to call the Web API.
+func HTTPClient(hc *http.Client) Option {
+	return func(c *Client) error {
+		if hc == nil {
+			return fmt.Errorf("http client is nil")
+		}
+		c.httpclient = hc
+		return nil
+	}
+}
+
+// Transport sets the round tripper of the HTTP client to call the Web API, e.g. to go through a proxy.
+func Transport(rt http.RoundTripper) Option {
+	return func(c *Client) error {
+		hc := *c.httpclient
+		hc.Transport = rt
+		c.httpclient = &hc
+		return nil
+	}
+}
+
+// DisableThrottle disables the client-side throttling by the rate limit tiers of the methods.
+// The rate-limited requests are still retried according to the Retry-After header.
+func DisableThrottle() Option {
+	return func(c *Client) error {
+		c.throttle = false
+		return nil
+	}
+}
+
+// RateLimit sets the client-side rate limit of the method.
+// The limit of chat.postMessage is applied per channel.
+func RateLimit(method string, lim Limit) Option {
+	return func(c *Client) error {
+		if lim.PerMinute < 0 || lim.Burst < 0 {
+			return fmt.Errorf("invalid rate limit: %+v", lim)
+		}
+		c.limiter.setLimit(method, lim)
+		return nil
+	}
+}
+
+// MaxRateLimitRetries sets the maximum number of retries of the request rate-limited with HTTP 429.
+// Zero disables the retries.
+func MaxRateLimitRetries(n int) Option {
+	return func(c *Client) error {
+		if n < 0 {
+			return fmt.Errorf("max rate limit retries must not be negative: %d", n)
+		}
+		c.maxRateLimitRetries = n
+		return nil
+	}
+}
+
+// SetRetryPolicy sets the policy of retrying the calls which failed transiently.
+// By default, only the calls of idempotent methods are retried.
+func SetRetryPolicy(p RetryPolicy) Option {
+	return func(c *Client) error {
+		if p.BaseDelay < 0 || p.MaxDelay < 0 {
+			return fmt.Errorf("invalid retry delay: %+v", p)
+		}
+		c.retryPolicy = p
+		return nil
+	}
+}
+
+// DedupPostMessage enables the retries of chat.postMessage on transient failures with a guard against double posts.
+// The client marks each message with a unique key in its metadata, and before a retry, looks for the message
+// marked with the key in the recent messages of the channel, so that the message is not posted twice.
+// required scopes: `channels:history`, `groups:history`, `im:history` or `mpim:history` for the channel
+func DedupPostMessage() Option {
+	return func(c *Client) error {
+		c.dedupPostMessage = true
+		return nil
+	}
+}
+
+// PageLimit sets the number of items to return in a page of the list methods, up to MaxPageLimit.
+// The list methods follow the cursor until all the items are fetched.
+// see. https://api.slack.com/docs/pagination
+func PageLimit(n int) Option {
+	return func(c *Client) error {
+		if n <= 0 || n > MaxPageLimit {
+			return fmt.Errorf("page limit must be between 1 and %d: %d", MaxPageLimit, n)
+		}
+		c.pageLimit = n
+		return nil
+	}
+}
@@ -0,0 +1,61 @@
+package webapi_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ikawaha/slackbot/slacktest"
+	"github.com/ikawaha/slackbot/webapi"
+)
+
+func TestBaseURL(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	srv.AddUser(webapi.User{ID: "U1", Name: "alice"})
+	tests := []struct {
+		name    string
+		url     string
+		wantErr bool
+	}{
+		{name: "with trailing slash", url: srv.URL()},
+		{name: "without trailing slash", url: strings.TrimSuffix(srv.URL(), "/")},
+		{name: "unsupported scheme", url: "ftp://example.com/api", wantErr: true},
+		{name: "relative", url: "api/", wantErr: true},
+		{name: "invalid", url: "http://[::1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := webapi.New("xoxb-token", webapi.BaseURL(tt.url))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			u, err := c.UsersInfo(context.Background(), "U1")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if u.Name != "alice" {
+				t.Errorf("want alice, got %+v", u)
+			}
+		})
+	}
+}
+
+func TestPageLimit(t *testing.T) {
+	for _, n := range []int{1, webapi.DefaultPageLimit, webapi.MaxPageLimit} {
+		if _, err := webapi.New("xoxb-token", webapi.PageLimit(n)); err != nil {
+			t.Errorf("%d: unexpected error: %v", n, err)
+		}
+	}
+	for _, n := range []int{0, -1, webapi.MaxPageLimit + 1} {
+		if _, err := webapi.New("xoxb-token", webapi.PageLimit(n)); err == nil {
+			t.Errorf("%d: want error", n)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package webapi
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+const (
+	// DefaultPageLimit is the default number of items to return in a page of the list methods.
+	DefaultPageLimit = 200
+
+	// MaxPageLimit is the maximum number of items that Slack returns in a page.
+	MaxPageLimit = 1000
+)
+
+// Paginator iterates over the pages of a list method by the cursor.
+// see. https://api.slack.com/docs/pagination
+//
+//	p := c.NewPaginator("users.list", url.Values{}, 0)
+//	for {
+//		var page UsersListResponse
+//		if !p.Next(ctx, &page) {
+//			break
+//		}
+//		...
+//	}
+//	if err := p.Err(); err != nil {
+//		...
+//	}
+type Paginator struct {
+	client *Client
+	method string
+	params url.Values
+	cursor string
+	done   bool
+	err    error
+}
+
+// NewPaginator creates a paginator of the method with the parameters.
+// The limit is the number of items to return in a page; zero or less means the client's page limit.
+func (c *Client) NewPaginator(method string, params url.Values, limit int) *Paginator {
+	if limit <= 0 {
+		limit = c.pageLimit
+	}
+	if limit > MaxPageLimit {
+		limit = MaxPageLimit
+	}
+	p := url.Values{}
+	for k, v := range params {
+		p[k] = append([]string(nil), v...)
+	}
+	p.Set("limit", strconv.Itoa(limit))
+	return &Paginator{
+		client: c,
+		method: method,
+		params: p,
+	}
+}
+
+// Next fetches the next page and decodes it into the result.
+// It returns false when there are no more pages or an error occurred.
+func (p *Paginator) Next(ctx context.Context, result interface{}) bool {
+	if p.done || p.err != nil {
+		return false
+	}
+	if p.cursor != "" {
+		p.params.Set("cursor", p.cursor)
+	}
+	r, err := p.client.call(ctx, p.method, "", p.params, result)
+	if err != nil {
+		p.err = fmt.Errorf("page error: cursor=%q, %w", p.cursor, err)
+		return false
+	}
+	p.cursor = r.ResponseMetadata.NextCursor
+	p.done = p.cursor == ""
+	return true
+}
+
+// Cursor returns the cursor of the next page, which resumes the pagination by SetCursor.
+func (p *Paginator) Cursor() string {
+	return p.cursor
+}
+
+// SetCursor sets the cursor to start the pagination from.
+func (p *Paginator) SetCursor(cursor string) {
+	p.cursor = cursor
+}
+
+// Done returns true, if all the pages have been fetched.
+func (p *Paginator) Done() bool {
+	return p.done
+}
+
+// Err returns the error that stopped the pagination.
+func (p *Paginator) Err() error {
+	return p.err
+}
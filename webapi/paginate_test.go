@@ -0,0 +1,121 @@
+package webapi_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/ikawaha/slackbot/slacktest"
+	"github.com/ikawaha/slackbot/webapi"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestClient_UsersList(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	for _, v := range []string{"alice", "bob", "carol"} {
+		srv.AddUser(webapi.User{ID: "U-" + v, Name: v})
+	}
+	var (
+		mux   sync.Mutex
+		calls int
+	)
+	c := newClient(t, srv, webapi.PageLimit(1), webapi.HTTPClient(&http.Client{
+		Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			mux.Lock()
+			calls++
+			mux.Unlock()
+			return http.DefaultTransport.RoundTrip(r)
+		}),
+	}))
+
+	us, err := c.UsersList(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(us) != 3 {
+		t.Fatalf("want 3 users, got %+v", us)
+	}
+	for i, v := range []string{"alice", "bob", "carol"} {
+		if us[i].Name != v {
+			t.Errorf("want %s, got %s", v, us[i].Name)
+		}
+	}
+	mux.Lock()
+	defer mux.Unlock()
+	if calls != 3 {
+		t.Errorf("want a page per user, got %d calls", calls)
+	}
+}
+
+func TestPaginator(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	for i := 0; i < 5; i++ {
+		srv.AddUser(webapi.User{ID: fmt.Sprintf("U%d", i), Name: fmt.Sprintf("user%d", i)})
+	}
+	c := newClient(t, srv)
+	ctx := context.Background()
+
+	p := c.NewPaginator("users.list", url.Values{}, 2)
+	var sizes []int
+	for {
+		var page webapi.UsersListResponse
+		if !p.Next(ctx, &page) {
+			break
+		}
+		sizes = append(sizes, len(page.Members))
+		if len(sizes) == 1 {
+			// resume the pagination from the cursor with another paginator.
+			q := c.NewPaginator("users.list", url.Values{}, 2)
+			q.SetCursor(p.Cursor())
+			var rest webapi.UsersListResponse
+			if !q.Next(ctx, &rest) || rest.Members[0].ID != "U2" {
+				t.Errorf("want to resume from U2, got %+v, %v", rest.Members, q.Err())
+			}
+		}
+	}
+	if p.Err() != nil {
+		t.Fatalf("unexpected error: %v", p.Err())
+	}
+	if fmt.Sprint(sizes) != "[2 2 1]" || !p.Done() || p.Cursor() != "" {
+		t.Errorf("want pages of [2 2 1], got %v, done: %v", sizes, p.Done())
+	}
+	var page webapi.UsersListResponse
+	if p.Next(ctx, &page) {
+		t.Error("want no more pages")
+	}
+
+	p = c.NewPaginator("users.list", url.Values{}, 2)
+	p.SetCursor("invalid")
+	if p.Next(ctx, &page) {
+		t.Error("want an error of the invalid cursor")
+	}
+	if !errors.Is(p.Err(), &webapi.APIError{Code: "invalid_cursor"}) || p.Done() {
+		t.Errorf("want invalid_cursor, got %v", p.Err())
+	}
+}
+
+func TestClient_ConversationsMembers(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	srv.AddChannel(webapi.Channel{ID: "C1", Name: "general"}, "U1", "U2", "U3")
+	c := newClient(t, srv, webapi.PageLimit(2))
+
+	got, err := c.ConversationsMembers(context.Background(), "C1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fmt.Sprint(got) != "[U1 U2 U3]" {
+		t.Errorf("want [U1 U2 U3], got %v", got)
+	}
+}
@@ -0,0 +1,264 @@
+package webapi
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultMaxRateLimitRetries is the number of retries of the rate-limited request unless another one is specified.
+const DefaultMaxRateLimitRetries = 3
+
+// Tier is the rate limit tier of the Web API methods.
+// see. https://api.slack.com/docs/rate-limits#tiers
+type Tier int
+
+// Rate limit tiers.
+const (
+	Tier1 Tier = iota + 1 // 1+ per minute
+	Tier2                 // 20+ per minute
+	Tier3                 // 50+ per minute
+	Tier4                 // 100+ per minute
+)
+
+// Limit returns the rate limit of the tier.
+func (t Tier) Limit() Limit {
+	switch t {
+	case Tier1:
+		return Limit{PerMinute: 1, Burst: 1}
+	case Tier2:
+		return Limit{PerMinute: 20, Burst: 20}
+	case Tier3:
+		return Limit{PerMinute: 50, Burst: 50}
+	case Tier4:
+		return Limit{PerMinute: 100, Burst: 100}
+	}
+	return Limit{}
+}
+
+// Limit is the rate limit of a method.
+// The zero value means no limit.
+type Limit struct {
+	// PerMinute is the number of requests allowed per minute.
+	PerMinute float64
+	// Burst is the number of requests allowed at once.
+	Burst int
+}
+
+// postMessageLimit is the limit of chat.postMessage, which is applied per channel.
+// see. https://api.slack.com/methods/chat.postMessage#rate_limiting
+var postMessageLimit = Limit{PerMinute: 60, Burst: 5}
+
+// methodTiers is the tiers of the methods the client calls.
+var methodTiers = map[string]Tier{
+	chatUpdateMethod:                 Tier3,
+	chatDeleteMethod:                 Tier3,
+	chatPostEphemeralMethod:          Tier4,
+	chatMeMessageMethod:              Tier3,
+	chatScheduleMessageMethod:        Tier3,
+	chatScheduledMessagesListMethod:  Tier3,
+	chatDeleteScheduledMessageMethod: Tier3,
+	conversationsListMethod:          Tier2,
+	conversationsInfoMethod:          Tier3,
+	conversationsMembersMethod:       Tier4,
+	conversationsJoinMethod:          Tier3,
+	conversationsOpenMethod:          Tier3,
+	conversationsCreateMethod:        Tier2,
+	conversationsInviteMethod:        Tier3,
+	conversationsArchiveMethod:       Tier2,
+	conversationsHistoryMethod:       Tier3,
+	conversationsRepliesMethod:       Tier3,
+	filesUploadMethod:                Tier2,
+	usersListMethod:                  Tier2,
+	usersInfoMethod:                  Tier4,
+	viewsOpenMethod:                  Tier4,
+	viewsPushMethod:                  Tier4,
+	viewsUpdateMethod:                Tier4,
+}
+
+// RateLimitStats is the statistics of the time requests waited for the rate limits.
+type RateLimitStats struct {
+	// Throttled is the number of requests delayed by the client-side throttling.
+	Throttled int64
+	// ThrottledWait is the total time the requests waited for the client-side throttling.
+	ThrottledWait time.Duration
+	// RateLimited is the number of the HTTP 429 Too Many Requests responses.
+	RateLimited int64
+	// RetryAfterWait is the total time the requests waited for the Retry-After of the responses.
+	RetryAfterWait time.Duration
+}
+
+// limiter throttles requests with the token bucket per method and key.
+type limiter struct {
+	mux     sync.Mutex
+	limits  map[string]Limit
+	buckets map[string]*bucket
+	stats   RateLimitStats
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func newLimiter() *limiter {
+	ret := limiter{
+		limits: map[string]Limit{
+			postMessageMethod: postMessageLimit,
+		},
+		buckets: map[string]*bucket{},
+	}
+	for k, v := range methodTiers {
+		ret.limits[k] = v.Limit()
+	}
+	return &ret
+}
+
+func (l *limiter) setLimit(method string, lim Limit) {
+	defer l.mux.Unlock()
+	l.mux.Lock()
+	l.limits[method] = lim
+}
+
+// reserve takes a token of the bucket and returns the time to wait until the token is available.
+func (l *limiter) reserve(method, key string, now time.Time) time.Duration {
+	defer l.mux.Unlock()
+	l.mux.Lock()
+	lim, ok := l.limits[method]
+	if !ok || lim.PerMinute <= 0 {
+		return 0
+	}
+	burst := float64(lim.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+	rate := lim.PerMinute / 60 // per second
+	id := method + "/" + key
+	b, ok := l.buckets[id]
+	if !ok {
+		b = &bucket{tokens: burst, last: now}
+		l.buckets[id] = b
+	}
+	if now.After(b.last) {
+		b.tokens += now.Sub(b.last).Seconds() * rate
+		if b.tokens > burst {
+			b.tokens = burst
+		}
+		b.last = now
+	}
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	d := time.Duration(-b.tokens / rate * float64(time.Second))
+	l.stats.Throttled++
+	l.stats.ThrottledWait += d
+	return d
+}
+
+func (l *limiter) rateLimited(d time.Duration) {
+	defer l.mux.Unlock()
+	l.mux.Lock()
+	l.stats.RateLimited++
+	l.stats.RetryAfterWait += d
+}
+
+// RateLimitStats returns the statistics of the time requests waited for the rate limits.
+func (c *Client) RateLimitStats() RateLimitStats {
+	defer c.limiter.mux.Unlock()
+	c.limiter.mux.Lock()
+	return c.limiter.stats
+}
+
+// do sends the request of the method, waiting for the client-side throttling.
+// The rate-limited request is retried after the time specified by the Retry-After header,
+// and the request failed transiently is retried according to the retry policy.
+// Each attempt takes a token of the client-side throttling, so that the retries stay within the limit.
+// The key distinguishes the rate limits of the same method, e.g. the channel of chat.postMessage.
+func (c *Client) do(req *http.Request, method, key string) (*http.Response, error) {
+	ctx := req.Context()
+	rewindable := req.Body == nil || req.GetBody != nil
+	for attempt, retry := 1, 0; ; {
+		if c.throttle {
+			if err := sleep(ctx, c.limiter.reserve(method, key, time.Now())); err != nil {
+				return nil, err
+			}
+		}
+		resp, err := c.httpclient.Do(req)
+		var d time.Duration
+		switch {
+		case !rewindable || ctx.Err() != nil:
+			return resp, err
+		case err == nil && resp.StatusCode == http.StatusTooManyRequests:
+			if retry >= c.maxRateLimitRetries {
+				return resp, nil
+			}
+			retry++
+			d = retryAfter(resp.Header)
+			c.limiter.rateLimited(d)
+			log.Printf("rate limited: method: %s, retry after %v", method, d)
+		case attempt < c.retryPolicy.MaxAttempts && c.retryable(method, resp, err):
+			d = c.retryPolicy.delay(attempt)
+			attempt++
+			log.Printf("retry: method: %s, attempt: %d, after %v, error: %v", method, attempt-1, d, retryReason(resp, err))
+		default:
+			return resp, err
+		}
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body) // nolint:errcheck
+			resp.Body.Close()
+		}
+		if err := sleep(ctx, d); err != nil {
+			return nil, err
+		}
+		if req, err = rewind(req); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func retryReason(resp *http.Response, err error) interface{} {
+	if err != nil {
+		return err
+	}
+	return resp.Status
+}
+
+// retryAfter returns the duration of the Retry-After header, or a second if it is missing.
+func retryAfter(h http.Header) time.Duration {
+	if v, err := strconv.Atoi(h.Get("Retry-After")); err == nil && v >= 0 {
+		return time.Duration(v) * time.Second
+	}
+	return time.Second
+}
+
+// rewind returns the copy of the request with the body to send it again.
+func rewind(req *http.Request) (*http.Request, error) {
+	ret := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		ret.Body = body
+	}
+	return ret, nil
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
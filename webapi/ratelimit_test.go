@@ -0,0 +1,120 @@
+package webapi
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestTier_Limit(t *testing.T) {
+	tests := []struct {
+		tier Tier
+		want float64
+	}{
+		{tier: Tier1, want: 1},
+		{tier: Tier2, want: 20},
+		{tier: Tier3, want: 50},
+		{tier: Tier4, want: 100},
+		{tier: Tier(0), want: 0},
+	}
+	for _, tt := range tests {
+		if got := tt.tier.Limit(); got.PerMinute != tt.want || got.Burst != int(tt.want) {
+			t.Errorf("tier %d: want %v per minute, got %+v", tt.tier, tt.want, got)
+		}
+	}
+}
+
+func TestLimiter_Reserve(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		method string
+		burst  int
+		wait   time.Duration // of the request after the burst
+	}{
+		{name: "tier 2", method: usersListMethod, burst: 20, wait: 3 * time.Second},
+		{name: "tier 3", method: conversationsHistoryMethod, burst: 50, wait: 1200 * time.Millisecond},
+		{name: "tier 4", method: usersInfoMethod, burst: 100, wait: 600 * time.Millisecond},
+		{name: "chat.postMessage", method: postMessageMethod, burst: 5, wait: time.Second},
+		{name: "unknown method", method: "unknown.method", burst: 1000, wait: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newLimiter()
+			for i := 0; i < tt.burst; i++ {
+				if d := l.reserve(tt.method, "", now); d != 0 {
+					t.Fatalf("request %d: want no wait, got %v", i+1, d)
+				}
+			}
+			if d := l.reserve(tt.method, "", now); d != tt.wait {
+				t.Errorf("want %v, got %v", tt.wait, d)
+			}
+			// the token is refilled at the rate.
+			if d := l.reserve(tt.method, "", now.Add(2*tt.wait)); d != 0 {
+				t.Errorf("want no wait after refill, got %v", d)
+			}
+		})
+	}
+}
+
+func TestLimiter_Reserve_PostMessagePerChannel(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := newLimiter()
+	for i := 0; i < postMessageLimit.Burst; i++ {
+		if d := l.reserve(postMessageMethod, "C1", now); d != 0 {
+			t.Fatalf("request %d: want no wait, got %v", i+1, d)
+		}
+	}
+	if d := l.reserve(postMessageMethod, "C2", now); d != 0 {
+		t.Errorf("want no wait for the other channel, got %v", d)
+	}
+	if d := l.reserve(postMessageMethod, "C1", now); d != time.Second {
+		t.Errorf("want 1s, got %v", d)
+	}
+	// the waiting requests queue up.
+	if d := l.reserve(postMessageMethod, "C1", now); d != 2*time.Second {
+		t.Errorf("want 2s, got %v", d)
+	}
+	want := RateLimitStats{Throttled: 2, ThrottledWait: 3 * time.Second}
+	if l.stats != want {
+		t.Errorf("want %+v, got %+v", want, l.stats)
+	}
+}
+
+func TestLimiter_SetLimit(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := newLimiter()
+	l.setLimit(usersInfoMethod, Limit{})
+	for i := 0; i < 1000; i++ {
+		if d := l.reserve(usersInfoMethod, "", now); d != 0 {
+			t.Fatalf("want no limit, got %v", d)
+		}
+	}
+	l.setLimit(usersInfoMethod, Limit{PerMinute: 6})
+	l.reserve(usersInfoMethod, "", now)
+	if d := l.reserve(usersInfoMethod, "", now); d != 10*time.Second {
+		t.Errorf("want 10s with the burst of 1, got %v", d)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		header string
+		want   time.Duration
+	}{
+		{header: "30", want: 30 * time.Second},
+		{header: "0", want: 0},
+		{header: "", want: time.Second},
+		{header: "-1", want: time.Second},
+		{header: "Wed, 21 Oct 2015 07:28:00 GMT", want: time.Second},
+	}
+	for _, tt := range tests {
+		h := http.Header{}
+		if tt.header != "" {
+			h["Retry-After"] = []string{tt.header}
+		}
+		if got := retryAfter(h); got != tt.want {
+			t.Errorf("%q: want %v, got %v", tt.header, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package webapi
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/ikawaha/slackbot/internal/slackapi"
+)
+
+// RetryPolicy represents how the client retries the Web API calls which failed transiently.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts including the first one. One or less disables retries.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry. It is doubled for each subsequent retry.
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between attempts.
+	MaxDelay time.Duration
+	// Retryable reports whether the failed call of the method should be retried.
+	// Either the response or the error is nil. If Retryable is nil, DefaultRetryable is used.
+	// It is not consulted for chat.postMessage with DedupPostMessage, which has its own retries.
+	Retryable func(method string, resp *http.Response, err error) bool
+}
+
+// DefaultRetryPolicy is the retry policy used unless another one is specified.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    10 * time.Second,
+}
+
+// idempotentMethods is the methods which are safe to call again.
+var idempotentMethods = map[string]bool{
+	chatUpdateMethod:                true,
+	chatScheduledMessagesListMethod: true,
+	conversationsListMethod:         true,
+	conversationsInfoMethod:         true,
+	conversationsMembersMethod:      true,
+	conversationsJoinMethod:         true,
+	conversationsOpenMethod:         true,
+	conversationsHistoryMethod:      true,
+	conversationsRepliesMethod:      true,
+	usersListMethod:                 true,
+	usersInfoMethod:                 true,
+	viewsUpdateMethod:               true,
+}
+
+// IsIdempotent returns true, if the method is safe to call again with the same parameters.
+func IsIdempotent(method string) bool {
+	return idempotentMethods[method]
+}
+
+// DefaultRetryable retries the calls of the idempotent methods failed with network errors,
+// timeouts of the HTTP client and 5xx responses.
+func DefaultRetryable(method string, resp *http.Response, err error) bool {
+	return IsIdempotent(method) && transient(resp, err)
+}
+
+// transient returns true, if the call failed with a transport error or a 5xx response.
+// The calls canceled by the caller never get here, since the client checks the context of the request first.
+func transient(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode >= http.StatusInternalServerError
+}
+
+func (p RetryPolicy) retryable(method string, resp *http.Response, err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(method, resp, err)
+	}
+	return DefaultRetryable(method, resp, err)
+}
+
+// retryable reports whether the failed call of the method should be retried by the client.
+// chat.postMessage with the dedup guard is retried only by postMessageWithDedup,
+// which checks the history before each retry, even if the retry policy allows it.
+func (c *Client) retryable(method string, resp *http.Response, err error) bool {
+	if c.dedupPostMessage && method == postMessageMethod {
+		return false
+	}
+	return c.retryPolicy.retryable(method, resp, err)
+}
+
+// delay returns the time to wait before the retry following the n-th failed attempt.
+func (p RetryPolicy) delay(n int) time.Duration {
+	return slackapi.Backoff(n, p.BaseDelay, p.MaxDelay, 0)
+}
+
+// dedupEventType is the metadata event type to find the message posted by a failed attempt.
+const (
+	dedupEventType = "slackbot_dedup"
+	dedupKeyField  = "slackbot_dedup_key"
+)
+
+// postMessageWithDedup posts the message and retries it on transient failures.
+// Before each retry, it looks for the message posted by the failed attempt by the metadata,
+// so that the retry does not produce a double post.
+func (c *Client) postMessageWithDedup(ctx context.Context, p messageParams) (*MessageResponse, error) {
+	key, err := newDedupKey()
+	if err != nil {
+		return nil, err
+	}
+	if p.Metadata == nil {
+		p.Metadata = &Metadata{EventType: dedupEventType}
+	}
+	payload := map[string]interface{}{}
+	for k, v := range p.Metadata.EventPayload {
+		payload[k] = v
+	}
+	payload[dedupKeyField] = key
+	p.Metadata = &Metadata{EventType: p.Metadata.EventType, EventPayload: payload}
+
+	since := time.Now().Add(-time.Minute)
+	for attempt := 1; ; attempt++ {
+		ret, err := c.postMessage(ctx, p)
+		if err == nil || attempt >= c.retryPolicy.MaxAttempts || !transientError(ctx, err) {
+			return ret, err
+		}
+		d := c.retryPolicy.delay(attempt)
+		log.Printf("retry: method: %s, attempt: %d, after %v, %v", postMessageMethod, attempt, d, err)
+		if err := sleep(ctx, d); err != nil {
+			return nil, err
+		}
+		m, found, findErr := c.findPostedMessage(ctx, p.Channel, p.ThreadTS, key, since)
+		if findErr != nil {
+			return nil, fmt.Errorf("%w (dedup check failed: %v)", err, findErr)
+		}
+		if found {
+			return &MessageResponse{OK: true, Channel: p.Channel, TS: m.TS, Message: *m}, nil
+		}
+	}
+}
+
+// transientError returns true, if the error of the call is a transport error or a 5xx response,
+// and the call has not been canceled by the caller.
+// Note that the timeout of the HTTP client is also reported as context.DeadlineExceeded, so the error
+// itself does not tell whether the caller gave up.
+func transientError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var ae *APIError
+	if errors.As(err, &ae) {
+		return ae.StatusCode >= http.StatusInternalServerError
+	}
+	return true
+}
+
+// findPostedMessage looks for the message with the dedup key in the recent messages of the channel or the thread.
+func (c *Client) findPostedMessage(ctx context.Context, channelID, threadTS, key string, since time.Time) (*Message, bool, error) {
+	method := conversationsHistoryMethod
+	params := url.Values{
+		"channel":              {channelID},
+		"oldest":               {strconv.FormatInt(since.Unix(), 10)},
+		"include_all_metadata": {"true"},
+		"limit":                {"100"},
+	}
+	if threadTS != "" {
+		method = conversationsRepliesMethod
+		params.Set("ts", threadTS)
+	}
+	var r struct {
+		Messages []Message `json:"messages"`
+	}
+	if err := c.Call(ctx, method, params, &r); err != nil {
+		return nil, false, err
+	}
+	for i, v := range r.Messages {
+		if v.Metadata != nil && v.Metadata.EventPayload[dedupKeyField] == key {
+			return &r.Messages[i], true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+func newDedupKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("dedup key error: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
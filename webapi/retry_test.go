@@ -0,0 +1,161 @@
+package webapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ikawaha/slackbot/slacktest"
+	"github.com/ikawaha/slackbot/webapi"
+)
+
+var fastRetryPolicy = webapi.SetRetryPolicy(webapi.RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   time.Millisecond,
+	MaxDelay:    time.Millisecond,
+})
+
+// failFirst returns the handler which fails the first n calls with the fail handler
+// and responds with the body after that.
+func failFirst(calls *int32, n int32, fail http.HandlerFunc, body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(calls, 1) <= n {
+			fail(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, body)
+	}
+}
+
+func serverError(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusInternalServerError)
+}
+
+func TestClient_Retry_ServerError(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	var calls int32
+	srv.HandleMethod("users.info", failFirst(&calls, 2, serverError, `{"ok":true,"user":{"id":"U1","name":"alice"}}`))
+
+	c := newClient(t, srv, fastRetryPolicy)
+	u, err := c.UsersInfo(context.Background(), "U1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if u.Name != "alice" || atomic.LoadInt32(&calls) != 3 {
+		t.Errorf("want alice after 3 calls, got %+v after %d calls", u, calls)
+	}
+
+	atomic.StoreInt32(&calls, 0)
+	srv.HandleMethod("users.info", failFirst(&calls, 100, serverError, ""))
+	_, err = c.UsersInfo(context.Background(), "U1")
+	var apiErr *webapi.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusInternalServerError {
+		t.Errorf("want the 500 error after the max attempts, got %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Errorf("want 3 attempts, got %d", got)
+	}
+}
+
+func TestClient_Retry_ClientTimeout(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	var calls int32
+	slow := func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(500 * time.Millisecond):
+		case <-r.Context().Done():
+		}
+	}
+	srv.HandleMethod("users.info", failFirst(&calls, 1, slow, `{"ok":true,"user":{"id":"U1","name":"alice"}}`))
+
+	// the timeout of the HTTP client is reported as context.DeadlineExceeded, but it is retried.
+	c := newClient(t, srv, fastRetryPolicy, webapi.HTTPClient(&http.Client{Timeout: 100 * time.Millisecond}))
+	if _, err := c.UsersInfo(context.Background(), "U1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("want 2 calls, got %d", got)
+	}
+
+	// the call canceled by the caller is not retried.
+	atomic.StoreInt32(&calls, 0)
+	c = newClient(t, srv, fastRetryPolicy)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := c.UsersInfo(ctx, "U1"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("want context.DeadlineExceeded, got %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("want 1 call, got %d", got)
+	}
+}
+
+func TestClient_Retry_NonIdempotent(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	var calls int32
+	srv.HandleMethod("chat.postMessage", failFirst(&calls, 1, serverError, `{"ok":true,"channel":"C1","ts":"1.000001"}`))
+
+	c := newClient(t, srv, fastRetryPolicy)
+	if _, err := c.PostMessage(context.Background(), "C1", "hello"); err == nil {
+		t.Error("want error")
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("want no retry, got %d calls", got)
+	}
+	if webapi.DefaultRetryable("chat.postMessage", &http.Response{StatusCode: http.StatusBadGateway}, nil) {
+		t.Error("want chat.postMessage not to be retryable")
+	}
+	if !webapi.DefaultRetryable("users.info", nil, errors.New("connection reset")) {
+		t.Error("want the network error to be retryable")
+	}
+	if webapi.DefaultRetryable("users.info", &http.Response{StatusCode: http.StatusBadRequest}, nil) {
+		t.Error("want 400 not to be retryable")
+	}
+}
+
+func TestClient_PostMessage_Dedup(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	var calls int32
+	// the message is posted, but the response is lost.
+	srv.HandleMethod("chat.postMessage", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		var m slacktest.PostedMessage
+		if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		srv.AddMessage(m)
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	c := newClient(t, srv, fastRetryPolicy, webapi.DedupPostMessage())
+	ret, err := c.PostMessage(context.Background(), "C1", "hello")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("want no double post, got %d calls", got)
+	}
+	if ret.TS == "" || ret.Message.Text != "hello" || ret.Message.Metadata == nil || ret.Message.Metadata.EventPayload["slackbot_dedup_key"] == "" {
+		t.Errorf("want the posted message with the dedup key, got %+v", ret)
+	}
+
+	// the message is not posted, so it is retried.
+	atomic.StoreInt32(&calls, 0)
+	srv.HandleMethod("chat.postMessage", failFirst(&calls, 1, serverError, `{"ok":true,"channel":"C2","ts":"1.000001"}`))
+	if _, err := c.PostMessage(context.Background(), "C2", "hello"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("want a retry, got %d calls", got)
+	}
+}
@@ -0,0 +1,117 @@
+package webapi
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	chatScheduleMessageMethod        = "chat.scheduleMessage"
+	chatScheduledMessagesListMethod  = "chat.scheduledMessages.list"
+	chatDeleteScheduledMessageMethod = "chat.deleteScheduledMessage"
+)
+
+// ScheduledMessage represents the message scheduled to be sent by Slack.
+type ScheduledMessage struct {
+	ID          string `json:"id"`
+	ChannelID   string `json:"channel_id"`
+	PostAt      int64  `json:"post_at"`
+	DateCreated int64  `json:"date_created"`
+	Text        string `json:"text"`
+}
+
+// PostTime returns the time when the message will be sent.
+func (m ScheduledMessage) PostTime() time.Time {
+	return time.Unix(m.PostAt, 0)
+}
+
+// ScheduleMessageResponse is the response of the chat.scheduleMessage API.
+type ScheduleMessageResponse struct {
+	OK                 bool    `json:"ok"`
+	Channel            string  `json:"channel"`
+	ScheduledMessageID string  `json:"scheduled_message_id"`
+	PostAt             int64   `json:"post_at"`
+	Message            Message `json:"message"`
+}
+
+// ScheduledMessagesListResponse is the response of the chat.scheduledMessages.list API.
+type ScheduledMessagesListResponse struct {
+	OK                bool               `json:"ok"`
+	ScheduledMessages []ScheduledMessage `json:"scheduled_messages"`
+}
+
+// ScheduleMessage schedules the message to be sent to the channel at the time by Slack.
+// The time must be in the future, and within 120 days.
+// The channel is the ID, or the name which starts with "#".
+// see. https://api.slack.com/methods/chat.scheduleMessage
+// required scopes: `chat:write`
+func (c *Client) ScheduleMessage(ctx context.Context, channel string, postAt time.Time, msg string, opts ...MessageOption) (*ScheduleMessageResponse, error) {
+	if postAt.IsZero() {
+		return nil, errors.New("time to post the scheduled message is empty")
+	}
+	p, err := c.newMessageParams(ctx, channel, msg, opts)
+	if err != nil {
+		return nil, err
+	}
+	p.PostAt = postAt.Unix()
+	var ret ScheduleMessageResponse
+	if err := c.Call(ctx, chatScheduleMessageMethod, p, &ret); err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// ScheduledMessages lists the messages scheduled by the bot and not sent yet.
+// The channel is the ID, or the name which starts with "#"; empty lists the messages of all channels.
+// The messages are limited to the ones to be sent between the oldest and the latest, if they are not zero.
+// see. https://api.slack.com/methods/chat.scheduledMessages.list
+func (c *Client) ScheduledMessages(ctx context.Context, channel string, oldest, latest time.Time) ([]ScheduledMessage, error) {
+	params := url.Values{}
+	if channel != "" {
+		channelID, err := c.channels.Resolve(ctx, channel)
+		if err != nil {
+			return nil, err
+		}
+		params.Set("channel", channelID)
+	}
+	if !oldest.IsZero() {
+		params.Set("oldest", strconv.FormatInt(oldest.Unix(), 10))
+	}
+	if !latest.IsZero() {
+		params.Set("latest", strconv.FormatInt(latest.Unix(), 10))
+	}
+	var ret []ScheduledMessage
+	p := c.NewPaginator(chatScheduledMessagesListMethod, params, 0)
+	for {
+		var page ScheduledMessagesListResponse
+		if !p.Next(ctx, &page) {
+			break
+		}
+		ret = append(ret, page.ScheduledMessages...)
+	}
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// DeleteScheduledMessage deletes the message scheduled by the bot before it is sent.
+// The channel is the ID, or the name which starts with "#".
+// see. https://api.slack.com/methods/chat.deleteScheduledMessage
+func (c *Client) DeleteScheduledMessage(ctx context.Context, channel, id string) error {
+	if id == "" {
+		return errors.New("ID of the scheduled message to delete is empty")
+	}
+	channelID, err := c.channels.Resolve(ctx, channel)
+	if err != nil {
+		return err
+	}
+	p := map[string]string{
+		"channel":              channelID,
+		"scheduled_message_id": id,
+	}
+	return c.Call(ctx, chatDeleteScheduledMessageMethod, p, nil)
+}
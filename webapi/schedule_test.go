@@ -0,0 +1,64 @@
+package webapi_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ikawaha/slackbot/slacktest"
+	"github.com/ikawaha/slackbot/webapi"
+)
+
+func TestClient_ScheduleMessage(t *testing.T) {
+	ctx := context.Background()
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	srv.AddChannel(webapi.Channel{ID: "C1", Name: "general"})
+	srv.AddChannel(webapi.Channel{ID: "C2", Name: "random"})
+	c := newClient(t, srv)
+	postAt := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	r, err := c.ScheduleMessage(ctx, "#general", postAt, "later")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.Channel != "C1" || r.ScheduledMessageID == "" || r.PostAt != postAt.Unix() {
+		t.Errorf("unexpected response: %+v", r)
+	}
+	if _, err := c.ScheduleMessage(ctx, "C2", postAt.Add(time.Hour), "much later"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ms, err := c.ScheduledMessages(ctx, "#general", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ms) != 1 || ms[0].ID != r.ScheduledMessageID || ms[0].Text != "later" || !ms[0].PostTime().Equal(postAt) {
+		t.Errorf("unexpected scheduled messages: %+v", ms)
+	}
+	if ms, err := c.ScheduledMessages(ctx, "", time.Time{}, time.Time{}); err != nil || len(ms) != 2 {
+		t.Errorf("want the messages of all channels, got %+v, %v", ms, err)
+	}
+	if ms, err := c.ScheduledMessages(ctx, "", postAt.Add(time.Minute), time.Time{}); err != nil || len(ms) != 1 || ms[0].ChannelID != "C2" {
+		t.Errorf("want the messages after the oldest, got %+v, %v", ms, err)
+	}
+
+	if err := c.DeleteScheduledMessage(ctx, "#general", r.ScheduledMessageID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ms := srv.ScheduledMessages(); len(ms) != 1 || ms[0].ChannelID != "C2" {
+		t.Errorf("want the message deleted, got %+v", ms)
+	}
+	if err := c.DeleteScheduledMessage(ctx, "C1", r.ScheduledMessageID); err == nil {
+		t.Error("want error for the deleted message")
+	}
+
+	var ae *webapi.APIError
+	if _, err := c.ScheduleMessage(ctx, "C1", time.Now().Add(-time.Hour), "past"); !errors.As(err, &ae) || ae.Code != "time_in_past" {
+		t.Errorf("want time_in_past, got %v", err)
+	}
+	if _, err := c.ScheduleMessage(ctx, "#unknown", postAt, "nowhere"); !errors.Is(err, webapi.ErrChannelNotFound) {
+		t.Errorf("want ErrChannelNotFound, got %v", err)
+	}
+}
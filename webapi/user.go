@@ -1,5 +1,9 @@
 package webapi
 
+import (
+	"time"
+)
+
 // UsersListResponse is the response of the users.list API.
 type UsersListResponse struct {
 	OK       bool   `json:"ok"`
@@ -10,6 +14,7 @@ type UsersListResponse struct {
 }
 
 // User represents the Slack user.
+// see. https://api.slack.com/types/user
 type User struct {
 	ID        string `json:"id,omitempty"`
 	TeamID    string `json:"team_id,omitempty"`
@@ -18,4 +23,116 @@ type User struct {
 	IsBot     bool   `json:"is_bot,omitempty"`
 	Deleted   bool   `json:"deleted,omitempty"`
 	IsAppUser bool   `json:"is_app_user"`
+	Color     string `json:"color,omitempty"`
+
+	// timezone
+	TZ       string `json:"tz,omitempty"`
+	TZLabel  string `json:"tz_label,omitempty"`
+	TZOffset int    `json:"tz_offset,omitempty"` // seconds east of UTC
+
+	// roles
+	IsAdmin           bool   `json:"is_admin,omitempty"`
+	IsOwner           bool   `json:"is_owner,omitempty"`
+	IsPrimaryOwner    bool   `json:"is_primary_owner,omitempty"`
+	IsRestricted      bool   `json:"is_restricted,omitempty"`       // multi-channel guest
+	IsUltraRestricted bool   `json:"is_ultra_restricted,omitempty"` // single-channel guest
+	IsStranger        bool   `json:"is_stranger,omitempty"`         // from another workspace through a shared channel
+	IsInvitedUser     bool   `json:"is_invited_user,omitempty"`
+	IsEmailConfirmed  bool   `json:"is_email_confirmed,omitempty"`
+	Has2FA            bool   `json:"has_2fa,omitempty"`
+	Locale            string `json:"locale,omitempty"`
+	Updated           int64  `json:"updated,omitempty"` // unix time
+
+	Profile        UserProfile     `json:"profile"`
+	EnterpriseUser *EnterpriseUser `json:"enterprise_user,omitempty"`
+}
+
+// UserProfile represents the profile of the Slack user.
+// see. https://api.slack.com/types/user#profile
+type UserProfile struct {
+	DisplayName           string `json:"display_name,omitempty"`
+	DisplayNameNormalized string `json:"display_name_normalized,omitempty"`
+	RealName              string `json:"real_name,omitempty"`
+	RealNameNormalized    string `json:"real_name_normalized,omitempty"`
+	FirstName             string `json:"first_name,omitempty"`
+	LastName              string `json:"last_name,omitempty"`
+	Email                 string `json:"email,omitempty"`
+	Phone                 string `json:"phone,omitempty"`
+	Title                 string `json:"title,omitempty"`
+	Team                  string `json:"team,omitempty"`
+	BotID                 string `json:"bot_id,omitempty"`
+	APIAppID              string `json:"api_app_id,omitempty"`
+
+	// status
+	StatusText       string `json:"status_text,omitempty"`
+	StatusEmoji      string `json:"status_emoji,omitempty"`
+	StatusExpiration int64  `json:"status_expiration,omitempty"` // unix time, zero if the status does not expire
+
+	// images
+	AvatarHash    string `json:"avatar_hash,omitempty"`
+	IsCustomImage bool   `json:"is_custom_image,omitempty"`
+	ImageOriginal string `json:"image_original,omitempty"`
+	Image24       string `json:"image_24,omitempty"`
+	Image32       string `json:"image_32,omitempty"`
+	Image48       string `json:"image_48,omitempty"`
+	Image72       string `json:"image_72,omitempty"`
+	Image192      string `json:"image_192,omitempty"`
+	Image512      string `json:"image_512,omitempty"`
+	Image1024     string `json:"image_1024,omitempty"`
+}
+
+// EnterpriseUser represents the user of the Enterprise Grid organization.
+type EnterpriseUser struct {
+	ID             string   `json:"id,omitempty"`
+	EnterpriseID   string   `json:"enterprise_id,omitempty"`
+	EnterpriseName string   `json:"enterprise_name,omitempty"`
+	IsAdmin        bool     `json:"is_admin,omitempty"`
+	IsOwner        bool     `json:"is_owner,omitempty"`
+	Teams          []string `json:"teams,omitempty"`
+}
+
+// DisplayName returns the name to address the user by: the display name, the real name or the username.
+func (u User) DisplayName() string {
+	switch {
+	case u.Profile.DisplayName != "":
+		return u.Profile.DisplayName
+	case u.Profile.RealName != "":
+		return u.Profile.RealName
+	case u.RealName != "":
+		return u.RealName
+	}
+	return u.Name
+}
+
+// Location returns the timezone of the user. If the timezone database has no such zone,
+// it returns the fixed zone of the offset.
+func (u User) Location() *time.Location {
+	if u.TZ != "" {
+		if loc, err := time.LoadLocation(u.TZ); err == nil {
+			return loc
+		}
+	}
+	if u.TZ == "" && u.TZOffset == 0 {
+		return time.UTC
+	}
+	return time.FixedZone(u.TZ, u.TZOffset)
+}
+
+// UpdatedAt returns the time when the user was last updated.
+func (u User) UpdatedAt() time.Time {
+	return time.Unix(u.Updated, 0)
+}
+
+// IsGuest returns true, if the user is a multi-channel or single-channel guest.
+func (u User) IsGuest() bool {
+	return u.IsRestricted || u.IsUltraRestricted
+}
+
+// UsersInfoResponse is the response of the users.info API.
+type UsersInfoResponse struct {
+	OK       bool   `json:"ok"`
+	Error    string `json:"error"`
+	Needed   string `json:"needed"`
+	Provided string `json:"provided"`
+	User     User   `json:"user"`
 }
@@ -0,0 +1,82 @@
+package webapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ikawaha/slackbot/blocks"
+)
+
+const (
+	viewsOpenMethod   = "views.open"
+	viewsPushMethod   = "views.push"
+	viewsUpdateMethod = "views.update"
+)
+
+// ViewResponse represents the response of the views.open, views.push and views.update API.
+type ViewResponse struct {
+	OK       bool   `json:"ok"`
+	Error    string `json:"error"`
+	Needed   string `json:"needed"`
+	Provided string `json:"provided"`
+	View     View   `json:"view"`
+}
+
+// View represents the view returned by the views API.
+type View struct {
+	ID              string          `json:"id"`
+	TeamID          string          `json:"team_id"`
+	Type            string          `json:"type"`
+	CallbackID      string          `json:"callback_id"`
+	PrivateMetadata string          `json:"private_metadata"`
+	ExternalID      string          `json:"external_id"`
+	Hash            string          `json:"hash"`
+	State           json.RawMessage `json:"state"`
+	RootViewID      string          `json:"root_view_id"`
+	PreviousViewID  string          `json:"previous_view_id"`
+	AppID           string          `json:"app_id"`
+	BotID           string          `json:"bot_id"`
+}
+
+type viewParams struct {
+	TriggerID  string       `json:"trigger_id,omitempty"`
+	ViewID     string       `json:"view_id,omitempty"`
+	ExternalID string       `json:"external_id,omitempty"`
+	Hash       string       `json:"hash,omitempty"`
+	View       *blocks.View `json:"view"`
+}
+
+// OpenView opens the modal view for the user who triggered the interaction.
+// see. https://api.slack.com/methods/views.open
+func (c *Client) OpenView(ctx context.Context, triggerID string, view *blocks.View) (*ViewResponse, error) {
+	return c.callViews(ctx, viewsOpenMethod, viewParams{TriggerID: triggerID, View: view})
+}
+
+// PushView pushes the modal view onto the stack of the root view.
+// see. https://api.slack.com/methods/views.push
+func (c *Client) PushView(ctx context.Context, triggerID string, view *blocks.View) (*ViewResponse, error) {
+	return c.callViews(ctx, viewsPushMethod, viewParams{TriggerID: triggerID, View: view})
+}
+
+// UpdateView updates the existing view identified by the view ID.
+// If the hash is not empty, the view is updated only if the hash matches the current state of the view.
+// see. https://api.slack.com/methods/views.update
+func (c *Client) UpdateView(ctx context.Context, viewID, hash string, view *blocks.View) (*ViewResponse, error) {
+	return c.callViews(ctx, viewsUpdateMethod, viewParams{ViewID: viewID, Hash: hash, View: view})
+}
+
+func (c *Client) callViews(ctx context.Context, method string, params viewParams) (*ViewResponse, error) {
+	if params.View == nil {
+		return nil, errors.New("view is nil")
+	}
+	if err := params.View.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid view: %w", err)
+	}
+	var ret ViewResponse
+	if _, err := c.call(ctx, method, "", params, &ret); err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}